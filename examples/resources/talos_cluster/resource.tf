@@ -0,0 +1,43 @@
+resource "talos_cluster" "cluster" {
+  # The base config from the cluster's talos_configuration.
+  base_config = talos_configuration.cluster.base_config
+
+  # How many nodes may be changed at the same time. Control plane changes are additionally
+  # limited so that etcd never loses quorum.
+  max_unavailable = 1
+
+  # Control plane nodes are changed before any worker. The first node bootstraps etcd.
+  control_nodes = [
+    {
+      name         = "control-1"
+      provision_ip = "192.168.122.15"
+      configure_ip = "192.168.122.100"
+      config = {
+        install = {
+          disk  = "/dev/vdb"
+          image = "ghcr.io/siderolabs/installer:latest"
+        }
+        network = {
+          hostname = "control-1"
+        }
+      }
+    },
+  ]
+
+  worker_nodes = [
+    {
+      name         = "worker-1"
+      provision_ip = "192.168.122.16"
+      configure_ip = "192.168.122.110"
+      config = {
+        install = {
+          disk  = "/dev/vdb"
+          image = "ghcr.io/siderolabs/installer:latest"
+        }
+        network = {
+          hostname = "worker-1"
+        }
+      }
+    },
+  ]
+}
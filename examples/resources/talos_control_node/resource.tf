@@ -253,27 +253,15 @@ TOC
   	  "4.5.6.7"
   	]
   	disable_pod_security_policy = false
-  	admission_control = [
-  	  {
-  		name = "PodSecurity"
-  		configuration = <<TOC
-apiVersion: pod-security.admission.config.k8s.io/v1alpha1
-kind:       PodSecurityConfiguration
-  defaults:
-    enforce:         baseline
-    enforce-version: latest
-    audit:           restricted
-    audit-version:   latest
-    warn:            restricted
-    warn-version:    latest
-  exemptions:
-    usernames: {}
-    runtimeClasses: {}
-    namespaces:
-    - kube-system
-TOC
-  	  }
-  	]
+  	pod_security = {
+  	  enforce           = "baseline"
+  	  enforce_version   = "latest"
+  	  audit             = "restricted"
+  	  audit_version     = "latest"
+  	  warn              = "restricted"
+  	  warn_version      = "latest"
+  	  exempt_namespaces = ["kube-system"]
+  	}
     }
 
     # Configure kube-proxy.
@@ -0,0 +1,15 @@
+resource "talos_kubernetes_upgrade" "cluster" {
+  # The version the cluster is currently running, and the version to move it to.
+  # Only a single minor version may be crossed at a time.
+  from_version = "1.24.2"
+  to_version   = "1.25.0"
+
+  # Control plane components are upgraded on these nodes first, one node at a time.
+  control_nodes = ["192.168.122.100", "192.168.122.101", "192.168.122.102"]
+
+  # The kubelet is then upgraded on every control and worker node.
+  worker_nodes = ["192.168.122.110"]
+
+  # The base config from the cluster's talos_configuration.
+  base_config = talos_configuration.cluster.base_config
+}
@@ -0,0 +1,16 @@
+resource "talos_machine_configuration_apply" "node" {
+  # Address of the node. Its maintenance API is used to apply the initial configuration,
+  # later changes are applied through the secure API.
+  endpoint = "192.168.122.100"
+
+  # Machine configuration rendered elsewhere, for example by `talosctl gen config`.
+  machine_configuration = file("${path.module}/controlplane.yaml")
+
+  # Credentials from the talosconfig generated alongside the machine configuration.
+  ca_certificate     = base64decode(var.talos_ca)
+  client_certificate = base64decode(var.talos_crt)
+  client_key         = base64decode(var.talos_key)
+
+  # How changes are applied once the node is configured.
+  apply_mode = "auto"
+}
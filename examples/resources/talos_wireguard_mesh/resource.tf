@@ -0,0 +1,23 @@
+resource "talos_wireguard_mesh" "overlay" {
+  # Keeps connections of members behind NAT open.
+  persistent_keepalive_interval = 25
+
+  members = [
+    { name = "control-1", endpoint = "192.168.122.100:51820", address = "10.10.0.1/24" },
+    { name = "control-2", endpoint = "192.168.122.101:51820", address = "10.10.0.2/24" },
+    # Members without an endpoint only connect to the others.
+    { name = "worker-1", address = "10.10.0.10/24" },
+  ]
+}
+
+resource "talos_worker_node" "worker_1" {
+  # ...
+
+  devices = {
+    wg0 = {
+      name      = "wg0"
+      addresses = ["10.10.0.10/24"]
+      wireguard = talos_wireguard_mesh.overlay.wireguard["worker-1"]
+    }
+  }
+}
@@ -6,23 +6,39 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
 	"github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/api/resource"
+	"github.com/talos-systems/talos/pkg/machinery/config/configloader"
 	v1alpha1 "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
 	machinetype "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"gopkg.in/yaml.v2"
+
+	"terraform-provider-talos/talos/datatypes"
 )
 
 type nodeResourceData interface {
 	TalosData(*v1alpha1.Config) (*v1alpha1.Config, error)
 	ReadInto(*v1alpha1.Config) error
 	Generate() error
+	ConfigPatches() []types.String
+	Runtime() (runtimeMode, error)
 }
 
 type readData struct {
@@ -44,6 +60,28 @@ func readConfig[N nodeResourceData](ctx context.Context, nodeData N, data readDa
 	}
 
 	defer conn.Close()
+
+	return fetchConfig(ctx, conn)
+}
+
+// fetchConfig retrieves the live machine configuration from a node over an established connection.
+func fetchConfig(ctx context.Context, conn *grpc.ClientConn) (out *v1alpha1.Config, errDesc string, err error) {
+	raw, errDesc, err := fetchConfigBytes(ctx, conn)
+	if err != nil {
+		return nil, errDesc, err
+	}
+
+	out = &v1alpha1.Config{}
+	err = yaml.Unmarshal(raw, out)
+	if err != nil {
+		return nil, "Unable to unmarshal Talos configuration into it's struct.", err
+	}
+
+	return
+}
+
+// fetchConfigBytes retrieves the live machine configuration document from a node over an established connection.
+func fetchConfigBytes(ctx context.Context, conn *grpc.ClientConn) (out []byte, errDesc string, err error) {
 	client := resource.NewResourceServiceClient(conn)
 	resourceResp, err := client.Get(ctx, &resource.GetRequest{
 		Type:      "MachineConfig",
@@ -59,10 +97,225 @@ func readConfig[N nodeResourceData](ctx context.Context, nodeData N, data readDa
 			fmt.Errorf("invalid message count from the Talos resource get request. Expected > 1 but got %d", len(resourceResp.Messages))
 	}
 
-	out = &v1alpha1.Config{}
-	err = yaml.Unmarshal(resourceResp.Messages[0].Resource.Spec.Yaml, out)
+	return resourceResp.Messages[0].Resource.Spec.Yaml, "", nil
+}
+
+// configEqual reports whether two machine configuration documents are semantically equal.
+func configEqual(a, b []byte) (bool, error) {
+	left, err := configloader.NewFromBytes(a)
 	if err != nil {
-		return nil, "Unable to unmarshal Talos configuration into it's struct.", err
+		return false, err
+	}
+
+	right, err := configloader.NewFromBytes(b)
+	if err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(left.Raw(), right.Raw()), nil
+}
+
+// readNormalized reads a node's live configuration into its state. Differences between the state and the node which
+// only come from defaults Talos fills in when generating a configuration from the node's base_config are suppressed,
+// see datatypes.Normalize.
+func readNormalized[N any, P interface {
+	*N
+	nodeResourceData
+}](plan P, machineType machinetype.Type, input *generate.Input, live *v1alpha1.Config) error {
+	prior := datatypes.Copy(*plan)
+	if err := plan.ReadInto(live); err != nil {
+		return err
+	}
+	read := datatypes.Copy(*plan)
+
+	generated, err := generate.Config(machineType, input)
+	if err != nil {
+		return fmt.Errorf("unable to generate the default configuration: %w", err)
+	}
+
+	var defaults N
+	if err := P(&defaults).ReadInto(generated); err != nil {
+		return err
+	}
+
+	*plan = datatypes.Normalize(prior, read, defaults)
+	return nil
+}
+
+// privateKeyPattern matches PEM encoded private keys, such as those of the base configuration's certificates.
+var privateKeyPattern = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[^-]*-----END [A-Z ]*PRIVATE KEY-----`)
+
+// maskSecrets returns a context masking the cluster's secrets from input and the sensitive attributes of data in the
+// provider's log output.
+func maskSecrets(ctx context.Context, input generate.Input, data any) context.Context {
+	secrets := datatypes.Secrets(data)
+	if input.ClusterSecret != "" {
+		secrets = append(secrets, input.ClusterSecret)
+	}
+	if input.Secrets != nil {
+		secrets = append(secrets, input.Secrets.BootstrapToken, input.Secrets.AESCBCEncryptionSecret)
+	}
+	if input.TrustdInfo != nil {
+		secrets = append(secrets, input.TrustdInfo.Token)
+	}
+
+	ctx = tflog.MaskLogRegexes(ctx, privateKeyPattern)
+	for _, secret := range secrets {
+		if secret != "" {
+			ctx = tflog.MaskLogStrings(ctx, secret)
+		}
+	}
+
+	return ctx
+}
+
+// fillString sets a derived value which isn't configured.
+func fillString(value *types.String, derived string) {
+	if value.Null || (!value.Unknown && value.Value == "") {
+		*value = types.String{Value: derived}
+	}
+}
+
+// defaultInstallImage returns the Talos installer image nodes are installed from by default.
+func defaultInstallImage(input generate.Input) string {
+	if input.InstallImage != "" {
+		return input.InstallImage
+	}
+
+	return generate.DefaultGenOptions().InstallImage
+}
+
+// kubernetesImage returns the image of a Kubernetes component from repository for the cluster's kubernetes_version,
+// as Talos generates it. defaultImage, the image of the version built into Talos, is used when the base configuration
+// holds no version.
+func kubernetesImage(input generate.Input, repository, defaultImage string) string {
+	if input.KubernetesVersion == "" {
+		return defaultImage
+	}
+
+	return fmt.Sprintf("%s:v%s", repository, strings.TrimPrefix(input.KubernetesVersion, "v"))
+}
+
+// componentImage is the image of a Kubernetes component and the attribute path it is configured at.
+type componentImage struct {
+	path  path.Path
+	image types.String
+}
+
+// warnImageVersions warns about the Kubernetes component images whose version differs from the cluster's
+// kubernetes_version. Such images are pinned by the user, derived images always match.
+func warnImageVersions(input generate.Input, images []componentImage) (diags diag.Diagnostics) {
+	if input.KubernetesVersion == "" {
+		return
+	}
+	version := "v" + strings.TrimPrefix(input.KubernetesVersion, "v")
+
+	for _, component := range images {
+		if component.image.Null || component.image.Unknown {
+			continue
+		}
+
+		image, _, _ := strings.Cut(component.image.Value, "@")
+		i := strings.LastIndex(image, ":")
+		if i < 0 || strings.Contains(image[i:], "/") {
+			continue
+		}
+
+		if tag := image[i+1:]; tag != version {
+			diags.AddAttributeWarning(component.path, "Kubernetes component version mismatch.",
+				fmt.Sprintf("The image %s is of version %s, while the cluster's kubernetes_version is %s.", component.image.Value, tag, version))
+		}
+	}
+
+	return
+}
+
+// generateWireguardKeys fills in the key pairs of the Wireguard devices. Private keys which aren't configured are
+// taken from the device with the same name in prior.
+func generateWireguardKeys(devices []datatypes.NetworkDevice, prior []datatypes.NetworkDevice) error {
+	for _, device := range devices {
+		if device.Wireguard == nil {
+			continue
+		}
+
+		var previous *datatypes.Wireguard
+		for _, p := range prior {
+			if p.Name.Value == device.Name.Value {
+				previous = p.Wireguard
+			}
+		}
+
+		if err := device.Wireguard.GenerateKeys(previous); err != nil {
+			return fmt.Errorf("unable to generate the Wireguard keys of device %s: %w", device.Name.Value, err)
+		}
+	}
+
+	return nil
+}
+
+// planGenerated replaces a node resource's plan with data, which holds the resource's configuration with its derived
+// values filled in. The attributes only known once the node is configured keep the values Terraform planned.
+func planGenerated(ctx context.Context, data any, req tfsdk.ModifyResourcePlanRequest, resp *tfsdk.ModifyResourcePlanResponse) {
+	var (
+		id, appliedMode types.String
+		rebooted        types.Bool
+	)
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("applied_mode"), &appliedMode)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("rebooted"), &rebooted)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, data)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("applied_mode"), appliedMode)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("rebooted"), rebooted)...)
+}
+
+// decodeConfig decodes a resource's configuration into target, and reports whether it could be. Lists, maps and
+// nested attributes which aren't known yet can't be held by target's Go types, so the configuration isn't decoded
+// until they are known, which Terraform does no later than apply. Errors decoding a known configuration are returned.
+func decodeConfig(ctx context.Context, config tfsdk.Config, target any) (bool, diag.Diagnostics) {
+	unknown := false
+	err := tftypes.Walk(config.Raw, func(_ *tftypes.AttributePath, value tftypes.Value) (bool, error) {
+		primitive := value.Type().Is(tftypes.String) || value.Type().Is(tftypes.Number) || value.Type().Is(tftypes.Bool)
+		unknown = unknown || (!value.IsKnown() && !primitive)
+		return !unknown, nil
+	})
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Unable to read the resource's configuration.", err.Error())
+		return false, diags
+	}
+
+	if unknown {
+		return false, nil
+	}
+
+	diags := config.Get(ctx, target)
+	return !diags.HasError(), diags
+}
+
+// validateTalosConfig checks the relationships between the attributes of a node's Talos configuration at p.
+func validateTalosConfig(config datatypes.TalosConfig, p path.Path) (diags diag.Diagnostics) {
+	if config.Network != nil {
+		paths := make([]path.Path, len(config.Network.Devices))
+		for i := range config.Network.Devices {
+			paths[i] = p.AtName("network").AtName("devices").AtListIndex(i)
+		}
+		diags.Append(datatypes.ValidateDevices(config.Network.Devices, paths)...)
+	}
+
+	diags.Append(datatypes.ValidateEncryption(config.Encryption, p.AtName("encryption"))...)
+
+	diags.Append(datatypes.ValidateKubelet(config.Kubelet, p.AtName("kubelet"))...)
+
+	diags.Append(datatypes.ValidateAPIServer(config.APIServer, p.AtName("apiserver"))...)
+
+	if config.Install != nil {
+		diags.Append(datatypes.ValidateInstallDisk(config.Install.Disk, config.Install.DiskSelector, p.AtName("install").AtName("disk_selector"))...)
 	}
 
 	return
@@ -79,6 +332,23 @@ func genConfig[N nodeResourceData](machineType machinetype.Type, input *generate
 		return nil, err
 	}
 
+	mode, err := nodeData.Runtime()
+	if err != nil {
+		return nil, err
+	}
+
+	newCfg, err = patchConfig(newCfg, nodeData.ConfigPatches(), mode, func(cfg *v1alpha1.Config) (any, error) {
+		read := reflect.New(reflect.TypeOf(nodeData).Elem()).Interface().(N)
+		if err := read.ReadInto(cfg); err != nil {
+			return nil, err
+		}
+
+		return read, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	confYaml, err := newCfg.Bytes()
 	if err != nil {
 		return nil, err
@@ -90,24 +360,278 @@ func genConfig[N nodeResourceData](machineType machinetype.Type, input *generate
 		return nil, err
 	}
 
-	out := rexp.ReplaceAll(confYaml, nil)
+	return rexp.ReplaceAll(confYaml, nil), nil
+}
+
+// applyModes maps the values accepted by a node's apply_mode attribute to their Talos API equivalent.
+var applyModes = map[string]machine.ApplyConfigurationRequest_Mode{
+	"auto":      machine.ApplyConfigurationRequest_AUTO,
+	"no_reboot": machine.ApplyConfigurationRequest_NO_REBOOT,
+	"reboot":    machine.ApplyConfigurationRequest_REBOOT,
+	"staged":    machine.ApplyConfigurationRequest_STAGED,
+	"try":       machine.ApplyConfigurationRequest_TRY,
+}
 
-	return out, nil
+// applyModeName returns the apply_mode attribute value for a Talos API apply mode.
+func applyModeName(mode machine.ApplyConfigurationRequest_Mode) string {
+	for name, m := range applyModes {
+		if m == mode {
+			return name
+		}
+	}
+
+	return strings.ToLower(mode.String())
+}
+
+// applyModeAttributes are the attributes shared by node resources to control how configuration is applied.
+var applyModeAttributes = map[string]tfsdk.Attribute{
+	"apply_mode": {
+		Type:     types.StringType,
+		Optional: true,
+		MarkdownDescription: "How configuration changes are applied to the node. One of `auto`, `no_reboot`, `reboot`, `staged` or `try`. " +
+			"Defaults to `reboot` when the node is provisioned and `auto` afterwards. Nodes in maintenance mode only support `auto` and `reboot`, " +
+			"other modes fall back to `reboot` when provisioning.",
+		Validators: []tfsdk.AttributeValidator{
+			datatypes.ValidateOneOf("auto", "no_reboot", "reboot", "staged", "try"),
+		},
+	},
+	"try_timeout": {
+		Type:     types.StringType,
+		Optional: true,
+		MarkdownDescription: "Duration, such as `1m`, after which a configuration applied with the `try` apply mode is rolled back. " +
+			"Rolling back allows recovering from a configuration that cuts off connectivity to the node. Requires the `try` apply mode.",
+		Validators: []tfsdk.AttributeValidator{
+			datatypes.ValidateDuration(),
+		},
+	},
+	"applied_mode": {
+		Type:                types.StringType,
+		Computed:            true,
+		MarkdownDescription: "The apply mode Talos actually used for the last configuration change.",
+	},
+	"rebooted": {
+		Type:                types.BoolType,
+		Computed:            true,
+		MarkdownDescription: "Whether the last configuration change rebooted the node.",
+	},
+}
+
+// validateApplyMode checks that a node's try_timeout, under p, is only set with the try apply mode. Values which
+// aren't known yet are checked once they are.
+func validateApplyMode(mode, tryTimeout types.String, p path.Path) (diags diag.Diagnostics) {
+	if mode.Unknown || tryTimeout.Null || tryTimeout.Unknown || tryTimeout.Value == "" {
+		return
+	}
+
+	if mode.Value != "try" {
+		diags.AddAttributeError(p.AtName("try_timeout"), "Invalid try_timeout.", "try_timeout can only be set when apply_mode is try.")
+	}
+
+	return
+}
+
+// applyRequest builds the apply configuration request for a node's apply_mode and try_timeout attributes.
+// fallback is used when no apply mode has been set.
+func applyRequest(yaml []byte, mode types.String, tryTimeout types.String, fallback machine.ApplyConfigurationRequest_Mode) (*machine.ApplyConfigurationRequest, error) {
+	req := &machine.ApplyConfigurationRequest{
+		Data: yaml,
+		Mode: fallback,
+	}
+
+	if !mode.Null && !mode.Unknown && mode.Value != "" {
+		m, ok := applyModes[mode.Value]
+		if !ok {
+			return nil, fmt.Errorf("invalid apply_mode %q, expected one of auto, no_reboot, reboot, staged or try", mode.Value)
+		}
+		req.Mode = m
+	}
+
+	if !tryTimeout.Null && !tryTimeout.Unknown && tryTimeout.Value != "" {
+		if req.Mode != machine.ApplyConfigurationRequest_TRY {
+			return nil, fmt.Errorf("try_timeout can only be set when apply_mode is try")
+		}
+
+		d, err := time.ParseDuration(tryTimeout.Value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse try_timeout: %w", err)
+		}
+		req.TryModeTimeout = durationpb.New(d)
+	}
+
+	return req, nil
+}
+
+// maintenanceRequest adjusts a request for a node in maintenance mode, which only supports the auto and reboot apply
+// modes. It returns a warning describing the adjustment, if one was made.
+func maintenanceRequest(req *machine.ApplyConfigurationRequest) (warning string) {
+	if req.Mode == machine.ApplyConfigurationRequest_AUTO || req.Mode == machine.ApplyConfigurationRequest_REBOOT {
+		return ""
+	}
+
+	warning = fmt.Sprintf("The %s apply mode is not supported by nodes in maintenance mode, the reboot apply mode was used instead.", applyModeName(req.Mode))
+	req.Mode = machine.ApplyConfigurationRequest_REBOOT
+	req.TryModeTimeout = nil
+
+	return warning
+}
+
+// appliedResult converts the result of applying a configuration into the applied_mode and rebooted attributes.
+func appliedResult(applied *machine.ApplyConfiguration) (mode types.String, rebooted types.Bool) {
+	return types.String{Value: applyModeName(applied.Mode)}, types.Bool{Value: applied.Mode == machine.ApplyConfigurationRequest_REBOOT}
 }
 
 func applyConfig(ctx context.Context, conn *grpc.ClientConn, yaml []byte, mode machine.ApplyConfigurationRequest_Mode) error {
+	_, err := applyConfigRequest(ctx, conn, &machine.ApplyConfigurationRequest{
+		Data: yaml,
+		Mode: mode,
+	})
+
+	return err
+}
+
+// applyConfigRequest applies a configuration and returns the result reported by the node.
+func applyConfigRequest(ctx context.Context, conn *grpc.ClientConn, req *machine.ApplyConfigurationRequest) (*machine.ApplyConfiguration, error) {
 	defer conn.Close()
 
 	client := machine.NewMachineServiceClient(conn)
-	_, err := client.ApplyConfiguration(ctx, &machine.ApplyConfigurationRequest{
-		Data: yaml,
-		Mode: mode,
+	resp, err := client.ApplyConfiguration(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Messages) < 1 {
+		return &machine.ApplyConfiguration{Mode: req.Mode}, nil
+	}
+
+	return resp.Messages[0], nil
+}
+
+// rebootsNode reports whether applying a request to the node at ip reboots it. Requests in the auto mode are dry run
+// to find out.
+func rebootsNode(ctx context.Context, input generate.Input, ip string, req *machine.ApplyConfigurationRequest) (bool, error) {
+	switch req.Mode {
+	case machine.ApplyConfigurationRequest_REBOOT:
+		return true, nil
+	case machine.ApplyConfigurationRequest_AUTO:
+	default:
+		return false, nil
+	}
+
+	conn, err := secureConn(ctx, input, net.JoinHostPort(ip, strconv.Itoa(talosPort)))
+	if err != nil {
+		return false, err
+	}
+
+	applied, err := applyConfigRequest(ctx, conn, &machine.ApplyConfigurationRequest{
+		Data:   req.Data,
+		Mode:   req.Mode,
+		DryRun: true,
 	})
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	return nil
+	return applied.Mode == machine.ApplyConfigurationRequest_REBOOT, nil
+}
+
+// changedSections returns the sections of a Talos configuration, such as machine.sysctls, that differ between two
+// rendered configurations. Top level settings which aren't sections, such as debug, are reported by their own key.
+func changedSections(before, after []byte) ([]string, error) {
+	var prior, next map[string]any
+	if err := yaml.Unmarshal(before, &prior); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(after, &next); err != nil {
+		return nil, err
+	}
+
+	sections := map[string]bool{}
+	for _, doc := range []map[string]any{prior, next} {
+		for root, value := range doc {
+			priorFields, priorOK := prior[root].(map[any]any)
+			nextFields, nextOK := next[root].(map[any]any)
+			if !priorOK || !nextOK {
+				if !reflect.DeepEqual(prior[root], next[root]) {
+					sections[root] = true
+				}
+				continue
+			}
+
+			for field := range value.(map[any]any) {
+				if !reflect.DeepEqual(priorFields[field], nextFields[field]) {
+					sections[fmt.Sprintf("%s.%v", root, field)] = true
+				}
+			}
+		}
+	}
+
+	changed := make([]string, 0, len(sections))
+	for section := range sections {
+		changed = append(changed, section)
+	}
+	sort.Strings(changed)
+
+	return changed, nil
+}
+
+// dryRun asks a node what applying a changed configuration would do without applying it, and describes the
+// outcome as warnings.
+func dryRun(ctx context.Context, input generate.Input, name, ip string, before, after []byte, req *machine.ApplyConfigurationRequest) (diags diag.Diagnostics) {
+	sections, err := changedSections(before, after)
+	if err != nil {
+		diags.AddWarning("Unable to determine configuration changes for node "+name+".", err.Error())
+		return
+	}
+
+	if err := reachable(ctx, input, ip); err != nil {
+		diags.AddWarning("Unable to dry run configuration changes for node "+name+".", err.Error())
+		return
+	}
+
+	conn, err := secureConn(ctx, input, net.JoinHostPort(ip, strconv.Itoa(talosPort)))
+	if err != nil {
+		diags.AddWarning("Unable to dry run configuration changes for node "+name+".", err.Error())
+		return
+	}
+
+	req.DryRun = true
+	applied, err := applyConfigRequest(ctx, conn, req)
+	if err != nil {
+		diags.AddWarning("Unable to dry run configuration changes for node "+name+".", err.Error())
+		return
+	}
+
+	summary := "Changes applied without a reboot."
+	switch applied.Mode {
+	case machine.ApplyConfigurationRequest_REBOOT:
+		summary = "Changes require a reboot."
+	case machine.ApplyConfigurationRequest_STAGED:
+		summary = "Changes are staged until the next reboot."
+	case machine.ApplyConfigurationRequest_TRY:
+		summary = "Changes are applied and rolled back if not confirmed."
+	}
+
+	detail := fmt.Sprintf("%s\nChanged sections: %s", summary, strings.Join(sections, ", "))
+	if applied.ModeDetails != "" {
+		detail += "\n\n" + applied.ModeDetails
+	}
+
+	diags.AddWarning(fmt.Sprintf("Node %s: %s", name, summary), detail)
+	for _, warning := range applied.Warnings {
+		diags.AddWarning("Talos configuration warning for node "+name+".", warning)
+	}
+
+	return
+}
+
+// kubernetesNodeName is the name a node registers itself with in Kubernetes, its configured hostname or otherwise the
+// name of its resource.
+func kubernetesNodeName(network *datatypes.NetworkConfig, name types.String) string {
+	if network != nil && !network.Hostname.Null && network.Hostname.Value != "" {
+		return network.Hostname.Value
+	}
+
+	return name.Value
 }
 
 func bootstrap(ctx context.Context, conn *grpc.ClientConn) error {
@@ -1,12 +1,19 @@
 package talos
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"reflect"
 	"terraform-provider-talos/talos/datatypes"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	talosmachine "github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/config"
 	"github.com/wI2L/jsondiff"
 
@@ -25,18 +32,6 @@ var (
 	nodeData *talosControlNodeResourceData = talosControlNodeResourceDataExample
 )
 
-type runtimeMode struct {
-	requiresInstall bool
-}
-
-func (m runtimeMode) String() string {
-	return fmt.Sprintf("runtimeMode(%v)", m.requiresInstall)
-}
-
-func (m runtimeMode) RequiresInstall() bool {
-	return m.requiresInstall
-}
-
 // TestValidateConfig checks whether an expected valid configuration using values in all fields can be created from a Terraform state struct.
 func TestValidateConfig(t *testing.T) {
 	confString, err := genConfig(machine.TypeControlPlane, &datatypes.InputBundleExample, talosControlNodeResourceDataExample)
@@ -52,7 +47,7 @@ func TestValidateConfig(t *testing.T) {
 	opts := []config.ValidationOption{config.WithLocal()}
 	opts = append(opts, config.WithStrict())
 
-	warnings, err := cfg.Validate(runtimeMode{requiresInstall: true}, opts...)
+	warnings, err := cfg.Validate(runtimeModeMetal, opts...)
 	for _, w := range warnings {
 		t.Logf("%s", w)
 	}
@@ -61,6 +56,37 @@ func TestValidateConfig(t *testing.T) {
 	}
 }
 
+// TestValidateRendered checks that a rendered configuration is validated for the node's runtime mode.
+func TestValidateRendered(t *testing.T) {
+	rendered, err := genConfig(machine.TypeControlPlane, &datatypes.InputBundleExample, talosControlNodeResourceDataExample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mode := range []types.String{{Null: true}, {Value: "metal"}, {Value: "cloud"}, {Value: "container"}} {
+		if diags := validateRendered(mode, rendered); diags.HasError() {
+			t.Errorf("configuration rejected in mode %s: %v", mode, diags)
+		}
+	}
+
+	diags := validateRendered(types.String{Value: "vm"}, rendered)
+	if !diags.HasError() {
+		t.Fatal("unknown runtime mode accepted")
+	}
+	if withPath, ok := diags[0].(diag.DiagnosticWithPath); !ok || !withPath.Path().Equal(path.Root("runtime_mode")) {
+		t.Errorf("expected an error at runtime_mode, got %v", diags)
+	}
+
+	// Only metal installs Talos to disk and requires an install disk.
+	noDisk := bytes.Replace(rendered, []byte("disk: /dev/sda"), []byte(`disk: ""`), 1)
+	if diags := validateRendered(types.String{Value: "metal"}, noDisk); !diags.HasError() {
+		t.Error("configuration without an install disk accepted in metal mode")
+	}
+	if diags := validateRendered(types.String{Value: "cloud"}, noDisk); diags.HasError() {
+		t.Errorf("configuration without an install disk rejected in cloud mode: %v", diags)
+	}
+}
+
 // TestConfigDataAll checks if converting a Terraform data struct describing a controlplane node is
 // converted into an expected Talos v1alpha1.Config struct.
 func TestConfigDataAll(t *testing.T) {
@@ -146,3 +172,366 @@ func TestReadControlConfig(t *testing.T) {
 		t.Fatalf("expected and actual state did not match\nchangelog %s", patch)
 	}
 }
+
+// TestApplyRequest checks that apply_mode and try_timeout are translated into an apply configuration request.
+func TestApplyRequest(t *testing.T) {
+	tests := []struct {
+		mode, timeout types.String
+		fallback      talosmachine.ApplyConfigurationRequest_Mode
+		expected      talosmachine.ApplyConfigurationRequest_Mode
+		valid         bool
+	}{
+		{types.String{Null: true}, types.String{Null: true}, talosmachine.ApplyConfigurationRequest_REBOOT, talosmachine.ApplyConfigurationRequest_REBOOT, true},
+		{types.String{Value: "no_reboot"}, types.String{Null: true}, talosmachine.ApplyConfigurationRequest_AUTO, talosmachine.ApplyConfigurationRequest_NO_REBOOT, true},
+		{types.String{Value: "try"}, types.String{Value: "1m"}, talosmachine.ApplyConfigurationRequest_AUTO, talosmachine.ApplyConfigurationRequest_TRY, true},
+		{types.String{Value: "staged"}, types.String{Value: "1m"}, talosmachine.ApplyConfigurationRequest_AUTO, talosmachine.ApplyConfigurationRequest_STAGED, false},
+		{types.String{Value: "try"}, types.String{Value: "soon"}, talosmachine.ApplyConfigurationRequest_AUTO, talosmachine.ApplyConfigurationRequest_TRY, false},
+		{types.String{Value: "sometimes"}, types.String{Null: true}, talosmachine.ApplyConfigurationRequest_AUTO, talosmachine.ApplyConfigurationRequest_AUTO, false},
+	}
+
+	for _, tt := range tests {
+		req, err := applyRequest(nil, tt.mode, tt.timeout, tt.fallback)
+		if (err == nil) != tt.valid {
+			t.Errorf("applyRequest(%q, %q): expected valid %t, got error %v", tt.mode.Value, tt.timeout.Value, tt.valid, err)
+			continue
+		}
+
+		if err == nil && req.Mode != tt.expected {
+			t.Errorf("applyRequest(%q, %q): expected mode %s but got %s", tt.mode.Value, tt.timeout.Value, tt.expected, req.Mode)
+		}
+	}
+
+	req := &talosmachine.ApplyConfigurationRequest{Mode: talosmachine.ApplyConfigurationRequest_TRY}
+	if warning := maintenanceRequest(req); warning == "" || req.Mode != talosmachine.ApplyConfigurationRequest_REBOOT {
+		t.Errorf("expected try mode to fall back to reboot during provisioning")
+	}
+}
+
+// TestValidateApplyMode checks that try_timeout is only accepted with the try apply mode.
+func TestValidateApplyMode(t *testing.T) {
+	tests := []struct {
+		mode, timeout types.String
+		valid         bool
+	}{
+		{types.String{Value: "try"}, types.String{Value: "1m"}, true},
+		{types.String{Value: "auto"}, types.String{Null: true}, true},
+		{types.String{Unknown: true}, types.String{Value: "1m"}, true},
+		{types.String{Value: "staged"}, types.String{Value: "1m"}, false},
+		{types.String{Null: true}, types.String{Value: "1m"}, false},
+	}
+
+	for _, tt := range tests {
+		diags := validateApplyMode(tt.mode, tt.timeout, path.Root("control_nodes").AtListIndex(0))
+		if diags.HasError() == tt.valid {
+			t.Errorf("validateApplyMode(%q, %q): expected valid %t, got %v", tt.mode.Value, tt.timeout.Value, tt.valid, diags)
+		}
+	}
+}
+
+// TestChangedSections checks that the sections differing between two rendered configurations are reported.
+func TestChangedSections(t *testing.T) {
+	before, err := genConfig(machine.TypeControlPlane, &datatypes.InputBundleExample, talosControlNodeResourceDataExample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := datatypes.Copy(*talosControlNodeResourceDataExample)
+	data.Sysctls = datatypes.MachineSysctls{"net.ipv4.ip_forward": datatypes.Wraps("1")}
+	data.Sysfs = datatypes.MachineSysfs{"devices.system.cpu.cpu0.cpufreq.scaling_governor": datatypes.Wraps("powersave")}
+	after, err := genConfig(machine.TypeControlPlane, &datatypes.InputBundleExample, &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sections, err := changedSections(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"machine.sysctls", "machine.sysfs"}; !reflect.DeepEqual(sections, expected) {
+		t.Errorf("expected changed sections %v but got %v", expected, sections)
+	}
+
+	// Top level settings are reported by their own key.
+	input := datatypes.InputBundleExample
+	input.Debug = !input.Debug
+	if after, err = genConfig(machine.TypeControlPlane, &input, talosControlNodeResourceDataExample); err != nil {
+		t.Fatal(err)
+	}
+	if sections, err = changedSections(before, after); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"debug"}; !reflect.DeepEqual(sections, expected) {
+		t.Errorf("expected changed sections %v but got %v", expected, sections)
+	}
+}
+
+// TestConfigEqual checks that machine configurations are compared by content rather than formatting.
+func TestConfigEqual(t *testing.T) {
+	confString, err := genConfig(machine.TypeControlPlane, &datatypes.InputBundleExample, talosControlNodeResourceDataExample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := configloader.NewFromBytes(confString)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reencoded, err := cfg.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if equal, err := configEqual(confString, reencoded); err != nil || !equal {
+		t.Errorf("expected re-encoded configuration to be equal, got %t %v", equal, err)
+	}
+
+	changed, err := applyConfigPatch(confString, "machine:\n  sysctls:\n    drift: \"1\"\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if equal, err := configEqual(confString, changed); err != nil || equal {
+		t.Errorf("expected changed configuration to differ, got %t %v", equal, err)
+	}
+}
+
+// TestReadNormalized checks that reading back the configuration applied to a node doesn't report the defaults Talos
+// fills in as changes, while changes made outside of Terraform are.
+func TestReadNormalized(t *testing.T) {
+	state := &talosControlNodeResourceData{
+		Name: datatypes.Wraps("test-node"),
+		TalosConfig: datatypes.TalosConfig{
+			Sysctls: datatypes.MachineSysctls{"net.ipv4.ip_forward": datatypes.Wraps("1")},
+		},
+	}
+	prior := datatypes.Copy(*state)
+
+	confString, err := genConfig(machine.TypeControlPlane, &datatypes.InputBundleExample, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := configloader.NewFromBytes(confString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	live := cfg.Raw().(*v1alpha1.Config)
+
+	if err := readNormalized(state, machine.TypeControlPlane, &datatypes.InputBundleExample, live); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(prior, *state) {
+		stateJSON, _ := json.MarshalIndent(state, "", "  ")
+		t.Fatalf("expected no drift after reading the applied configuration, got %s", stateJSON)
+	}
+
+	live.MachineConfig.MachineSysctls["net.ipv4.ip_forward"] = "0"
+	if err := readNormalized(state, machine.TypeControlPlane, &datatypes.InputBundleExample, live); err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Sysctls["net.ipv4.ip_forward"].Value != "0" {
+		t.Fatalf("expected the out of band sysctl change to be read, got %v", state.Sysctls)
+	}
+}
+
+// TestReadGeneratedNormalized checks that a control node's state, with the values filled in by Generate, is unchanged
+// by reading back the configuration applied from it.
+func TestReadGeneratedNormalized(t *testing.T) {
+	base, err := json.Marshal(datatypes.InputBundleExample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := &talosControlNodeResourceData{
+		Name: datatypes.Wraps("test-node"),
+		TalosConfig: datatypes.TalosConfig{
+			Install: &datatypes.InstallConfig{},
+			Network: &datatypes.NetworkConfig{Hostname: datatypes.Wraps("test-node")},
+		},
+		BaseConfig: types.String{Value: string(base)},
+	}
+	if err := state.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	prior := datatypes.Copy(*state)
+
+	confString, err := genConfig(machine.TypeControlPlane, &datatypes.InputBundleExample, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := configloader.NewFromBytes(confString)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := readNormalized(state, machine.TypeControlPlane, &datatypes.InputBundleExample, cfg.Raw().(*v1alpha1.Config)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(prior, *state) {
+		priorJSON, _ := json.Marshal(prior.TalosConfig)
+		stateJSON, _ := json.Marshal(state.TalosConfig)
+		patch, _ := jsondiff.CompareJSON(priorJSON, stateJSON)
+		t.Fatalf("expected no drift after reading the applied configuration\nchangelog %s", patch)
+	}
+}
+
+// TestGenerateKeepsValues checks that generating a control node's derived values keeps configured values, keeps the
+// base configuration's secrets out of state and reuses the Wireguard private key of the node's previous state.
+func TestGenerateKeepsValues(t *testing.T) {
+	base, err := json.Marshal(datatypes.InputBundleExample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := func() *talosControlNodeResourceData {
+		return &talosControlNodeResourceData{
+			Name: datatypes.Wraps("test-node"),
+			TalosConfig: datatypes.TalosConfig{
+				Install: &datatypes.InstallConfig{Image: datatypes.Wraps("example.com/installer:v1")},
+				Network: &datatypes.NetworkConfig{
+					Hostname: datatypes.Wraps("test-node"),
+					Devices: []datatypes.NetworkDevice{
+						{
+							Name:      datatypes.Wraps("wg0"),
+							Wireguard: &datatypes.Wireguard{PrivateKey: types.String{Null: true}},
+						},
+					},
+				},
+			},
+			BaseConfig: types.String{Value: string(base)},
+		}
+	}
+
+	prior := node()
+	if err := prior.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if prior.Install.Image.Value != "example.com/installer:v1" {
+		t.Errorf("configured install image replaced by %q", prior.Install.Image.Value)
+	}
+	if prior.Etcd.CaCrt.Value != string(datatypes.InputBundleExample.Certs.Etcd.Crt) {
+		t.Error("etcd CA certificate not derived from the base configuration")
+	}
+	if !prior.Etcd.CaKey.Null {
+		t.Error("etcd CA key of the base configuration copied into the node's state")
+	}
+
+	plan := node()
+	if err := plan.generate(prior); err != nil {
+		t.Fatal(err)
+	}
+
+	priorKey, planKey := prior.Network.Devices[0].Wireguard, plan.Network.Devices[0].Wireguard
+	if priorKey.PrivateKey.Value == "" || planKey.PrivateKey.Value != priorKey.PrivateKey.Value {
+		t.Errorf("private key %q not kept from prior state %q", planKey.PrivateKey.Value, priorKey.PrivateKey.Value)
+	}
+	if planKey.PublicKey.Value != priorKey.PublicKey.Value {
+		t.Errorf("public key %q not derived from the kept private key", planKey.PublicKey.Value)
+	}
+}
+
+// TestGenerateImages checks that the images which aren't pinned are derived from the base configuration's
+// kubernetes_version, and that pinned images of another version are kept and warned about.
+func TestGenerateImages(t *testing.T) {
+	input := datatypes.InputBundleExample
+	input.KubernetesVersion = "1.23.5"
+	base, err := json.Marshal(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := &talosControlNodeResourceData{
+		Name: datatypes.Wraps("test-node"),
+		TalosConfig: datatypes.TalosConfig{
+			Network:   &datatypes.NetworkConfig{Hostname: datatypes.Wraps("test-node")},
+			Scheduler: &datatypes.SchedulerConfig{Image: datatypes.Wraps("registry.local:5000/kube-scheduler:v1.24.2@sha256:abc")},
+		},
+		BaseConfig: types.String{Value: string(base)},
+	}
+	if err := node.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for image, expected := range map[types.String]string{
+		node.Kubelet.Image:           "ghcr.io/siderolabs/kubelet:v1.23.5",
+		node.APIServer.Image:         "k8s.gcr.io/kube-apiserver:v1.23.5",
+		node.ControllerManager.Image: "k8s.gcr.io/kube-controller-manager:v1.23.5",
+		node.Proxy.Image:             "k8s.gcr.io/kube-proxy:v1.23.5",
+		node.Scheduler.Image:         "registry.local:5000/kube-scheduler:v1.24.2@sha256:abc",
+	} {
+		if image.Value != expected {
+			t.Errorf("expected image %s, got %s", expected, image.Value)
+		}
+	}
+
+	diags := warnImageVersions(input, []componentImage{
+		{path.Root("kubelet"), node.Kubelet.Image},
+		{path.Root("scheduler"), node.Scheduler.Image},
+		{path.Root("local"), datatypes.Wraps("registry.local:5000/kube-proxy")},
+	})
+	if len(diags) != 1 || diags.HasError() {
+		t.Fatalf("expected a single warning, got %v", diags)
+	}
+	if withPath, ok := diags[0].(diag.DiagnosticWithPath); !ok || !withPath.Path().Equal(path.Root("scheduler")) {
+		t.Errorf("expected the warning at the pinned scheduler image, got %v", diags[0])
+	}
+}
+
+func TestValidateTalosConfig(t *testing.T) {
+	if diags := validateTalosConfig(*datatypes.TalosConfigExample, path.Root("config")); diags.HasError() {
+		t.Errorf("example configuration rejected: %v", diags)
+	}
+}
+
+// TestDecodeConfig checks that a configuration is only decoded once the lists it holds are known, and that errors
+// decoding a known configuration are reported.
+func TestDecodeConfig(t *testing.T) {
+	schema := tfsdk.Schema{Attributes: map[string]tfsdk.Attribute{
+		"name":           {Type: types.StringType, Optional: true},
+		"config_patches": {Type: types.ListType{ElemType: types.StringType}, Optional: true},
+	}}
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":           tftypes.String,
+		"config_patches": tftypes.List{ElementType: tftypes.String},
+	}}
+
+	type data struct {
+		Name    types.String   `tfsdk:"name"`
+		Patches []types.String `tfsdk:"config_patches"`
+	}
+
+	for _, tc := range []struct {
+		name    string
+		patches tftypes.Value
+		target  any
+		decoded bool
+		err     bool
+	}{
+		{"known", tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "a")}), &data{}, true, false},
+		{"unknown list", tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, tftypes.UnknownValue), &data{}, false, false},
+		{"unknown element", tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, tftypes.UnknownValue)}), &data{}, true, false},
+		{"mismatched target", tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil), &struct {
+			Name types.String `tfsdk:"name"`
+		}{}, false, true},
+	} {
+		config := tfsdk.Config{
+			Schema: schema,
+			Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+				"name":           tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"config_patches": tc.patches,
+			}),
+		}
+
+		decoded, diags := decodeConfig(context.Background(), config, tc.target)
+		if decoded != tc.decoded || diags.HasError() != tc.err {
+			t.Errorf("%s: expected decoded %t and error %t, got %t and %v", tc.name, tc.decoded, tc.err, decoded, diags)
+		}
+	}
+}
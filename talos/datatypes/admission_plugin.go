@@ -1,6 +1,8 @@
 package datatypes
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
 	"gopkg.in/yaml.v3"
@@ -11,7 +13,7 @@ func (planAdmissionPluginConfig AdmissionPluginConfig) Data() (interface{}, erro
 	var admissionConfig v1alpha1.Unstructured
 
 	if err := yaml.Unmarshal([]byte(planAdmissionPluginConfig.Configuration.Value), &admissionConfig); err != nil {
-		return &v1alpha1.AdmissionPluginConfig{}, nil
+		return nil, fmt.Errorf("invalid configuration of admission plugin %s: %w", planAdmissionPluginConfig.Name.Value, err)
 	}
 
 	admissionPluginConfig := &v1alpha1.AdmissionPluginConfig{
@@ -29,11 +31,27 @@ type TalosAdmissionPluginConfigs struct {
 func (talosAdmissionPluginConfigs TalosAdmissionPluginConfigs) ReadFunc() []ConfigReadFunc {
 	funs := []ConfigReadFunc{
 		func(planConfig *TalosConfig) (err error) {
-			if planConfig.APIServer.AdmissionPlugins == nil {
-				planConfig.APIServer.AdmissionPlugins = make([]AdmissionPluginConfig, 0)
+			if planConfig.APIServer == nil {
+				planConfig.APIServer = &APIServerConfig{}
+			}
+
+			// The PodSecurity plugin is read into pod_security, unless it was configured as an admission plugin.
+			typed := true
+			for _, plugin := range planConfig.APIServer.AdmissionPlugins {
+				if plugin.Name.Value == podSecurityPlugin {
+					typed = false
+				}
 			}
 
+			// The plugins read replace those in state, rather than being appended to them.
+			planConfig.APIServer.AdmissionPlugins = make([]AdmissionPluginConfig, 0)
+
 			for _, config := range talosAdmissionPluginConfigs.AdmissionControlConfigs {
+				if podSecurity, ok := readPodSecurity(config); ok && typed {
+					planConfig.APIServer.PodSecurity = podSecurity
+					continue
+				}
+
 				conf := AdmissionPluginConfig{
 					Name: readString(config.Name()),
 				}
@@ -1,10 +1,16 @@
 package datatypes
 
 import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
-	"gopkg.in/yaml.v3"
 )
 
+// apiServerConfigDir holds the machine files rendered from the apiserver's audit policy and OIDC settings. It's
+// mounted into the apiserver at the same path.
+const apiServerConfigDir = "/var/etc/kubernetes/apiserver"
+
 // Data copies data from terraform state types to talos types.
 func (planAPIServer APIServerConfig) Data() (interface{}, error) {
 	apiServer := &v1alpha1.APIServerConfig{}
@@ -24,16 +30,24 @@ func (planAPIServer APIServerConfig) Data() (interface{}, error) {
 		apiServer.DisablePodSecurityPolicyConfig = planAPIServer.DisablePSP.Value
 	}
 
-	for i, pluginYaml := range planAPIServer.AdmissionPlugins {
-		apiServer.AdmissionControlConfig = append(apiServer.AdmissionControlConfig, &v1alpha1.AdmissionPluginConfig{
-			PluginName: pluginYaml.Name.Value,
-		})
+	for _, planPlugin := range planAPIServer.AdmissionPlugins {
+		plugin, err := planPlugin.Data()
+		if err != nil {
+			return &v1alpha1.APIServerConfig{}, err
+		}
+		apiServer.AdmissionControlConfig = append(apiServer.AdmissionControlConfig, plugin.(*v1alpha1.AdmissionPluginConfig))
+	}
 
-		var plugin v1alpha1.Unstructured
-		if err := yaml.Unmarshal([]byte(pluginYaml.Configuration.Value), &plugin); err != nil {
+	if planAPIServer.PodSecurity != nil {
+		plugin, err := planAPIServer.PodSecurity.Data()
+		if err != nil {
 			return &v1alpha1.APIServerConfig{}, err
 		}
-		apiServer.AdmissionControlConfig[i].PluginConfiguration = plugin
+		apiServer.AdmissionControlConfig = append(apiServer.AdmissionControlConfig, plugin.(*v1alpha1.AdmissionPluginConfig))
+	}
+
+	if err := planAPIServer.setAuthentication(apiServer); err != nil {
+		return &v1alpha1.APIServerConfig{}, err
 	}
 
 	for _, san := range planAPIServer.CertSANS {
@@ -54,11 +68,117 @@ func (planAPIServer APIServerConfig) Data() (interface{}, error) {
 	return apiServer, nil
 }
 
+// setAuthentication renders the audit policy and OIDC settings into the apiserver's arguments, and mounts the files
+// they refer to.
+func (planAPIServer APIServerConfig) setAuthentication(apiServer *v1alpha1.APIServerConfig) error {
+	if planAPIServer.AuditPolicy == nil && planAPIServer.OIDC == nil {
+		return nil
+	}
+
+	if apiServer.ExtraArgsConfig == nil {
+		apiServer.ExtraArgsConfig = map[string]string{}
+	}
+
+	if planAPIServer.AuditPolicy != nil {
+		apiServer.ExtraArgsConfig[auditPolicyArg] = auditPolicyPath
+	}
+
+	if planAPIServer.OIDC != nil {
+		args, err := planAPIServer.OIDC.Data()
+		if err != nil {
+			return err
+		}
+		for arg, value := range args.(map[string]string) {
+			apiServer.ExtraArgsConfig[arg] = value
+		}
+	}
+
+	files, err := planAPIServer.files()
+	if err != nil {
+		return err
+	}
+	if len(files) > 0 {
+		apiServer.ExtraVolumesConfig = append(apiServer.ExtraVolumesConfig, v1alpha1.VolumeMountConfig{
+			VolumeHostPath:  apiServerConfigDir,
+			VolumeMountPath: apiServerConfigDir,
+			VolumeReadOnly:  true,
+		})
+	}
+
+	return nil
+}
+
+// files returns the machine files holding the audit policy and the OIDC provider's CA.
+func (planAPIServer APIServerConfig) files() (files []*v1alpha1.MachineFile, err error) {
+	if planAPIServer.AuditPolicy != nil {
+		policy, err := planAPIServer.AuditPolicy.Data()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, apiServerFile(auditPolicyPath, policy.(string)))
+	}
+
+	if planAPIServer.OIDC != nil && !planAPIServer.OIDC.CA.Null {
+		files = append(files, apiServerFile(oidcCAPath, planAPIServer.OIDC.CA.Value))
+	}
+
+	return files, nil
+}
+
+func apiServerFile(path, content string) *v1alpha1.MachineFile {
+	return &v1alpha1.MachineFile{
+		FileContent:     content,
+		FilePermissions: 0o444,
+		FilePath:        path,
+		FileOp:          "create",
+	}
+}
+
+// isAPIServerFile reports whether a machine file is one rendered by the provider for the apiserver.
+func isAPIServerFile(path string) bool {
+	return strings.HasPrefix(path, apiServerConfigDir+"/")
+}
+
+func readAPIServerFile(files Files, path string) (string, bool) {
+	for _, file := range files {
+		if file.Path() == path {
+			return file.Content(), true
+		}
+	}
+
+	return "", false
+}
+
+// readAuthentication parses the audit policy and OIDC settings out of the apiserver's arguments and the files they
+// refer to. Arguments which were configured as extra arguments are kept as such.
+func (apiServer *APIServerConfig) readAuthentication(prior map[string]types.String, files Files) {
+	raw := apiServer.AuditPolicy != nil && !apiServer.AuditPolicy.Policy.Null
+	apiServer.AuditPolicy = nil
+	if _, ok := prior[auditPolicyArg]; !ok && apiServer.ExtraArgs[auditPolicyArg].Value == auditPolicyPath {
+		if policy, ok := readAPIServerFile(files, auditPolicyPath); ok {
+			apiServer.AuditPolicy = readAuditPolicy(policy, raw)
+			delete(apiServer.ExtraArgs, auditPolicyArg)
+		}
+	}
+
+	apiServer.OIDC = nil
+	if _, ok := prior[oidcIssuerURLArg]; !ok {
+		apiServer.OIDC, _ = readOIDC(apiServer.ExtraArgs, files)
+	}
+
+	if len(apiServer.ExtraArgs) <= 0 {
+		apiServer.ExtraArgs = nil
+	}
+}
+
 func (planAPIServer APIServerConfig) zero() bool {
 	return mkString(planAPIServer.Image).zero() &&
 		len(planAPIServer.ExtraArgs) <= 0 &&
 		mkBool(planAPIServer.DisablePSP).zero() &&
 		len(planAPIServer.AdmissionPlugins) <= 0 &&
+		planAPIServer.PodSecurity == nil &&
+		planAPIServer.AuditPolicy == nil &&
+		planAPIServer.OIDC == nil &&
 		len(planAPIServer.CertSANS) <= 0 &&
 		len(planAPIServer.Env) <= 0 &&
 		len(planAPIServer.ExtraVolumes) <= 0
@@ -72,6 +192,13 @@ func (planAPIServer APIServerConfig) DataFunc() [](func(*v1alpha1.Config) error)
 				return err
 			}
 			cfg.ClusterConfig.APIServerConfig = ins.(*v1alpha1.APIServerConfig)
+
+			files, err := planAPIServer.files()
+			if err != nil {
+				return err
+			}
+			cfg.MachineConfig.MachineFiles = append(cfg.MachineConfig.MachineFiles, files...)
+
 			return nil
 		},
 	}
@@ -79,6 +206,8 @@ func (planAPIServer APIServerConfig) DataFunc() [](func(*v1alpha1.Config) error)
 
 type TalosAPIServerConfig struct {
 	*v1alpha1.APIServerConfig
+	// Files are the node's machine files, which hold the audit policy and the OIDC provider's CA.
+	Files Files
 }
 
 func (talosAPIServerConfig TalosAPIServerConfig) ReadFunc() []ConfigReadFunc {
@@ -95,12 +224,17 @@ func (talosAPIServerConfig TalosAPIServerConfig) ReadFunc() []ConfigReadFunc {
 				planConfig.APIServer.Image.Value = (&v1alpha1.APIServerConfig{}).Image()
 			}
 
+			// Read along with the admission plugins, if there are any.
+			planConfig.APIServer.PodSecurity = nil
+
 			planConfig.APIServer.DisablePSP = readBool(talosAPIServerConfig.DisablePodSecurityPolicyConfig)
 			planConfig.APIServer.Env = readStringMap(talosAPIServerConfig.EnvConfig)
 
+			prior := planConfig.APIServer.ExtraArgs
 			if len(talosAPIServerConfig.ExtraArgsConfig) > 0 {
 				planConfig.APIServer.ExtraArgs = readStringMap(talosAPIServerConfig.ExtraArgsConfig)
 			}
+			planConfig.APIServer.readAuthentication(prior, talosAPIServerConfig.Files)
 
 			if len(talosAPIServerConfig.CertSANs) > 0 {
 				planConfig.APIServer.CertSANS = readStringList(talosAPIServerConfig.CertSANs)
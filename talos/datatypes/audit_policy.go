@@ -0,0 +1,130 @@
+package datatypes
+
+import (
+	"bytes"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	auditPolicyArg        = "audit-policy-file"
+	auditPolicyPath       = apiServerConfigDir + "/audit-policy.yaml"
+	auditPolicyAPIVersion = "audit.k8s.io/v1"
+	auditPolicyKind       = "Policy"
+)
+
+// auditPolicy is the policy of the apiserver's audit log.
+type auditPolicy struct {
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	OmitStages []string    `yaml:"omitStages,omitempty"`
+	Rules      []auditRule `yaml:"rules"`
+}
+
+type auditRule struct {
+	Level           string                `yaml:"level"`
+	Users           []string              `yaml:"users,omitempty"`
+	UserGroups      []string              `yaml:"userGroups,omitempty"`
+	Verbs           []string              `yaml:"verbs,omitempty"`
+	Resources       []auditGroupResources `yaml:"resources,omitempty"`
+	Namespaces      []string              `yaml:"namespaces,omitempty"`
+	NonResourceURLs []string              `yaml:"nonResourceURLs,omitempty"`
+	OmitStages      []string              `yaml:"omitStages,omitempty"`
+}
+
+type auditGroupResources struct {
+	Group         string   `yaml:"group,omitempty"`
+	Resources     []string `yaml:"resources,omitempty"`
+	ResourceNames []string `yaml:"resourceNames,omitempty"`
+}
+
+// Data renders the audit policy document.
+func (planAuditPolicy AuditPolicyConfig) Data() (any, error) {
+	if !planAuditPolicy.Policy.Null {
+		return planAuditPolicy.Policy.Value, nil
+	}
+
+	policy := auditPolicy{
+		APIVersion: auditPolicyAPIVersion,
+		Kind:       auditPolicyKind,
+		Rules:      make([]auditRule, 0, len(planAuditPolicy.Rules)),
+	}
+	setStringList(planAuditPolicy.OmitStages, &policy.OmitStages)
+
+	for _, planRule := range planAuditPolicy.Rules {
+		rule := auditRule{}
+		setString(planRule.Level, &rule.Level)
+		setStringList(planRule.Users, &rule.Users)
+		setStringList(planRule.UserGroups, &rule.UserGroups)
+		setStringList(planRule.Verbs, &rule.Verbs)
+		setStringList(planRule.Namespaces, &rule.Namespaces)
+		setStringList(planRule.NonResourceURLs, &rule.NonResourceURLs)
+		setStringList(planRule.OmitStages, &rule.OmitStages)
+
+		for _, planResources := range planRule.Resources {
+			resources := auditGroupResources{}
+			setString(planResources.Group, &resources.Group)
+			setStringList(planResources.Resources, &resources.Resources)
+			setStringList(planResources.ResourceNames, &resources.ResourceNames)
+			rule.Resources = append(rule.Resources, resources)
+		}
+
+		policy.Rules = append(policy.Rules, rule)
+	}
+
+	out, err := yaml.Marshal(&policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(out), nil
+}
+
+// readAuditPolicy reads an audit policy document. It's read into rules unless raw is set, or the policy can't be
+// represented by them.
+func readAuditPolicy(document string, raw bool) *AuditPolicyConfig {
+	auditPolicyConfig := &AuditPolicyConfig{Policy: types.String{Value: document}}
+	if raw {
+		return auditPolicyConfig
+	}
+
+	var policy auditPolicy
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(document)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&policy); err != nil || policy.APIVersion != auditPolicyAPIVersion || policy.Kind != auditPolicyKind {
+		return auditPolicyConfig
+	}
+
+	auditPolicyConfig = &AuditPolicyConfig{
+		Rules:      make([]AuditRule, 0, len(policy.Rules)),
+		OmitStages: readStringList(policy.OmitStages),
+		Policy:     types.String{Null: true},
+	}
+
+	for _, rule := range policy.Rules {
+		auditRule := AuditRule{
+			Level:           readString(rule.Level),
+			Users:           readStringList(rule.Users),
+			UserGroups:      readStringList(rule.UserGroups),
+			Verbs:           readStringList(rule.Verbs),
+			Namespaces:      readStringList(rule.Namespaces),
+			NonResourceURLs: readStringList(rule.NonResourceURLs),
+			OmitStages:      readStringList(rule.OmitStages),
+		}
+
+		for _, resources := range rule.Resources {
+			groupResources := AuditGroupResources{
+				Group:         types.String{Null: true},
+				Resources:     readStringList(resources.Resources),
+				ResourceNames: readStringList(resources.ResourceNames),
+			}
+			mkString(resources.Group).read(&groupResources.Group)
+			auditRule.Resources = append(auditRule.Resources, groupResources)
+		}
+
+		auditPolicyConfig.Rules = append(auditPolicyConfig.Rules, auditRule)
+	}
+
+	return auditPolicyConfig
+}
@@ -0,0 +1,63 @@
+package datatypes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestAuditPolicy(t *testing.T) {
+	policy := &AuditPolicyConfig{
+		Rules: []AuditRule{
+			{
+				Level: Wraps("None"),
+				Users: Wrapsl("system:kube-proxy"),
+				Verbs: Wrapsl("watch"),
+				Resources: []AuditGroupResources{
+					{Group: types.String{Null: true}, Resources: Wrapsl("endpoints", "services")},
+					{Group: Wraps("apps"), Resources: Wrapsl("deployments"), ResourceNames: Wrapsl("coredns")},
+				},
+			},
+			{Level: Wraps("RequestResponse"), Namespaces: Wrapsl("kube-system"), OmitStages: Wrapsl("ResponseStarted")},
+			{Level: Wraps("Metadata"), NonResourceURLs: Wrapsl("/healthz*")},
+		},
+		OmitStages: Wrapsl("RequestReceived"),
+		Policy:     types.String{Null: true},
+	}
+
+	res, err := policy.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if read := readAuditPolicy(res.(string), false); !reflect.DeepEqual(read, policy) {
+		t.Fatalf("expected: %v, got: %v", policy, read)
+	}
+
+	// A policy configured as a raw document is kept as one.
+	if read := readAuditPolicy(res.(string), true); read.Rules != nil || read.Policy.Value != res.(string) {
+		t.Fatalf("expected the raw policy to be kept, got %v", read)
+	}
+}
+
+func TestAuditPolicyRaw(t *testing.T) {
+	document := `apiVersion: audit.k8s.io/v1
+kind: Policy
+omitManagedFields: true
+rules:
+  - level: Metadata
+`
+
+	res, err := AuditPolicyConfig{Policy: Wraps(document)}.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.(string) != document {
+		t.Fatalf("expected the raw policy to be rendered as is, got %q", res)
+	}
+
+	if read := readAuditPolicy(document, false); read.Rules != nil || read.Policy.Value != document {
+		t.Fatalf("expected a policy which can't be represented by rules to be read raw, got %v", read)
+	}
+}
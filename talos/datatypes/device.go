@@ -1,6 +1,7 @@
 package datatypes
 
 import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
 )
 
@@ -10,6 +11,14 @@ func (planDevice NetworkDevice) Data() (interface{}, error) {
 
 	device.DeviceInterface = planDevice.Name.Value
 
+	if planDevice.Selector != nil {
+		device.DeviceSelector = &v1alpha1.NetworkDeviceSelector{}
+		setString(planDevice.Selector.BusPath, &device.DeviceSelector.NetworkDeviceBus)
+		setString(planDevice.Selector.HardwareAddr, &device.DeviceSelector.NetworkDeviceHardwareAddress)
+		setString(planDevice.Selector.PCIID, &device.DeviceSelector.NetworkDevicePCIID)
+		setString(planDevice.Selector.Driver, &device.DeviceSelector.NetworkDeviceKernelDriver)
+	}
+
 	for _, address := range planDevice.Addresses {
 		device.DeviceAddresses = append(device.DeviceAddresses, address.Value)
 	}
@@ -90,7 +99,14 @@ type TalosNetworkInterface struct {
 }
 
 func readInterface(talosNetworkInterface TalosNetworkInterface, device *NetworkDevice) (err error) {
-	device.Name = readString(talosNetworkInterface.Interface())
+	device.Name = types.String{Null: true}
+	mkString(talosNetworkInterface.Interface()).read(&device.Name)
+
+	device.Selector = nil
+	if talosNetworkInterface.DeviceSelector != nil {
+		device.Selector = readDeviceSelector(talosNetworkInterface.DeviceSelector)
+	}
+
 	device.Addresses = readStringList(talosNetworkInterface.Addresses())
 
 	device.DHCP = readBool(talosNetworkInterface.DHCP())
@@ -98,6 +114,7 @@ func readInterface(talosNetworkInterface TalosNetworkInterface, device *NetworkD
 	device.Ignore = readBool(talosNetworkInterface.Ignore())
 	device.MTU = readInt(talosNetworkInterface.MTU())
 
+	device.Routes = nil
 	for _, route := range talosNetworkInterface.Routes() {
 		device.Routes = append(device.Routes, readRoute(route))
 	}
@@ -114,6 +131,7 @@ func readInterface(talosNetworkInterface TalosNetworkInterface, device *NetworkD
 		device.VIP = readVIPConfig(talosNetworkInterface.VIPConfig())
 	}
 
+	device.VLANs = nil
 	for _, vlan := range talosNetworkInterface.Vlans() {
 		device.VLANs = append(device.VLANs, readVLAN(vlan))
 	}
@@ -128,13 +146,39 @@ func readInterface(talosNetworkInterface TalosNetworkInterface, device *NetworkD
 	return
 }
 
+func readDeviceSelector(talosSelector *v1alpha1.NetworkDeviceSelector) *DeviceSelector {
+	selector := &DeviceSelector{
+		BusPath:      types.String{Null: true},
+		HardwareAddr: types.String{Null: true},
+		PCIID:        types.String{Null: true},
+		Driver:       types.String{Null: true},
+	}
+
+	mkString(talosSelector.NetworkDeviceBus).read(&selector.BusPath)
+	mkString(talosSelector.NetworkDeviceHardwareAddress).read(&selector.HardwareAddr)
+	mkString(talosSelector.NetworkDevicePCIID).read(&selector.PCIID)
+	mkString(talosSelector.NetworkDeviceKernelDriver).read(&selector.Driver)
+
+	return selector
+}
+
+// matches reports whether the plan device describes the same network device as talosNetworkInterface.
+// Devices chosen by a selector are matched by their selector, all others by their interface name.
+func (talosNetworkInterface TalosNetworkInterface) matches(device NetworkDevice) bool {
+	if talosNetworkInterface.DeviceSelector != nil {
+		return device.Selector != nil && *readDeviceSelector(talosNetworkInterface.DeviceSelector) == *device.Selector
+	}
+
+	return device.Selector == nil && device.Name.Value == talosNetworkInterface.Interface()
+}
+
 func (talosNetworkInterface TalosNetworkInterface) ReadFunc() []ConfigReadFunc {
 	funs := []ConfigReadFunc{
 		func(planConfig *TalosConfig) (err error) {
 			inList := false
-			for _, device := range planConfig.Network.Devices {
-				if device.Name.Value == talosNetworkInterface.Interface() {
-					readInterface(talosNetworkInterface, &device)
+			for i := range planConfig.Network.Devices {
+				if talosNetworkInterface.matches(planConfig.Network.Devices[i]) {
+					readInterface(talosNetworkInterface, &planConfig.Network.Devices[i])
 					inList = true
 				}
 			}
@@ -0,0 +1,45 @@
+package datatypes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+)
+
+func TestDeviceSelectorData(t *testing.T) {
+	device := NetworkDevice{
+		Name: types.String{Null: true},
+		Selector: &DeviceSelector{
+			BusPath:      types.String{Null: true},
+			HardwareAddr: Wraps("*:f0:ab"),
+			PCIID:        types.String{Null: true},
+			Driver:       Wraps("virtio_net"),
+		},
+		Addresses: Wrapsl("192.168.1.10/24"),
+	}
+
+	res, err := device.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &v1alpha1.NetworkDeviceSelector{NetworkDeviceHardwareAddress: "*:f0:ab", NetworkDeviceKernelDriver: "virtio_net"}
+	talosDevice := res.(*v1alpha1.Device)
+	if talosDevice.DeviceInterface != "" || !reflect.DeepEqual(talosDevice.DeviceSelector, expected) {
+		t.Fatalf("expected interface \"\" and selector %v, got %q and %v", expected, talosDevice.DeviceInterface, talosDevice.DeviceSelector)
+	}
+
+	read := &TalosConfig{Network: &NetworkConfig{Devices: []NetworkDevice{{Name: Wraps("eth0")}, device}}}
+	if _, err := ApplyReadFunc(read, TalosNetworkInterface{Device: talosDevice}.ReadFunc()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(read.Network.Devices) != 2 {
+		t.Fatalf("expected the selected device to be matched by its selector, got devices %v", read.Network.Devices)
+	}
+	if got := read.Network.Devices[1]; !got.Name.Null || !reflect.DeepEqual(got.Selector, device.Selector) {
+		t.Fatalf("expected: %v, got: %v", device, got)
+	}
+}
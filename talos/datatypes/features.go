@@ -0,0 +1,72 @@
+package datatypes
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+)
+
+// Data copies data from terraform state types to talos types.
+func (planFeatures FeaturesConfig) Data() (any, error) {
+	features := &v1alpha1.FeaturesConfig{}
+
+	if !planFeatures.RBAC.Null {
+		rbac := planFeatures.RBAC.Value
+		features.RBAC = &rbac
+	}
+
+	return features, nil
+}
+
+// DataFunc sets the configured features. Features which aren't configured keep the defaults generated for the
+// cluster's version contract, such as RBAC being enabled from Talos v0.11 on.
+func (planFeatures FeaturesConfig) DataFunc() [](func(*v1alpha1.Config) error) {
+	return [](func(*v1alpha1.Config) error){
+		func(cfg *v1alpha1.Config) error {
+			if cfg.MachineConfig.MachineFeatures == nil {
+				cfg.MachineConfig.MachineFeatures = &v1alpha1.FeaturesConfig{}
+			}
+
+			if !planFeatures.RBAC.Null {
+				rbac := planFeatures.RBAC.Value
+				cfg.MachineConfig.MachineFeatures.RBAC = &rbac
+			}
+
+			return nil
+		},
+	}
+}
+
+func (stateFeatures *FeaturesConfig) Read(features any) error {
+	if features == nil {
+		return fmt.Errorf("nil talos FeaturesConfig pointer provided to Read function")
+	}
+	featuresConfig := features.(*v1alpha1.FeaturesConfig)
+
+	stateFeatures.RBAC = types.Bool{Null: true}
+	if featuresConfig.RBAC != nil {
+		stateFeatures.RBAC = types.Bool{Value: *featuresConfig.RBAC}
+	}
+
+	return nil
+}
+
+type TalosFeaturesConfig struct {
+	*v1alpha1.FeaturesConfig
+}
+
+func (talosFeaturesConfig TalosFeaturesConfig) ReadFunc() []ConfigReadFunc {
+	funs := []ConfigReadFunc{
+		func(planConfig *TalosConfig) (err error) {
+			if talosFeaturesConfig.FeaturesConfig == nil || talosFeaturesConfig.RBAC == nil {
+				planConfig.Features = nil
+				return nil
+			}
+
+			planConfig.Features = &FeaturesConfig{}
+			return planConfig.Features.Read(talosFeaturesConfig.FeaturesConfig)
+		},
+	}
+	return funs
+}
@@ -0,0 +1,83 @@
+package datatypes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+)
+
+func TestFeaturesData(t *testing.T) {
+	disabled := false
+
+	tests := []struct {
+		input  FeaturesConfig
+		output *v1alpha1.FeaturesConfig
+	}{
+		{
+			input:  FeaturesConfig{RBAC: types.Bool{Null: true}},
+			output: &v1alpha1.FeaturesConfig{},
+		}, {
+			input:  FeaturesConfig{RBAC: types.Bool{Value: false}},
+			output: &v1alpha1.FeaturesConfig{RBAC: &disabled},
+		}, {
+			input:  *FeaturesConfigExample,
+			output: TalosFeaturesConfigExample,
+		},
+	}
+
+	for _, tc := range tests {
+		res, err := tc.input.Data()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(res.(*v1alpha1.FeaturesConfig), tc.output) {
+			t.Fatalf("expected: %v, got: %v", tc.output, res)
+		}
+	}
+}
+
+func TestFeaturesRead(t *testing.T) {
+	disabled := false
+
+	tests := []struct {
+		input  *v1alpha1.FeaturesConfig
+		output FeaturesConfig
+	}{
+		{
+			input:  &v1alpha1.FeaturesConfig{},
+			output: FeaturesConfig{RBAC: types.Bool{Null: true}},
+		}, {
+			input:  &v1alpha1.FeaturesConfig{RBAC: &disabled},
+			output: FeaturesConfig{RBAC: types.Bool{Value: false}},
+		}, {
+			input:  TalosFeaturesConfigExample,
+			output: *FeaturesConfigExample,
+		},
+	}
+
+	for _, tc := range tests {
+		features := &FeaturesConfig{}
+		err := features.Read(tc.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(*features, tc.output) {
+			t.Fatalf("expected: %v, got: %v", tc.output, *features)
+		}
+	}
+}
+
+// TestFeaturesDefaults checks that features which aren't configured keep the defaults of the version contract.
+func TestFeaturesDefaults(t *testing.T) {
+	enabled := true
+	cfg := &v1alpha1.Config{MachineConfig: &v1alpha1.MachineConfig{MachineFeatures: &v1alpha1.FeaturesConfig{RBAC: &enabled}}}
+
+	if err := ApplyDataFunc(cfg, FeaturesConfig{RBAC: types.Bool{Null: true}}.DataFunc()); err != nil {
+		t.Fatal(err)
+	}
+	if rbac := cfg.MachineConfig.MachineFeatures.RBAC; rbac == nil || !*rbac {
+		t.Fatalf("expected the generated RBAC default to be kept, got %v", rbac)
+	}
+}
@@ -37,11 +37,12 @@ type TalosFiles struct {
 func (talosFiles TalosFiles) ReadFunc() []ConfigReadFunc {
 	funs := []ConfigReadFunc{
 		func(planConfig *TalosConfig) (err error) {
-			if planConfig.Files == nil {
-				planConfig.Files = make([]File, 0)
-			}
-
 			for _, file := range talosFiles.Files {
+				// The files rendered for the apiserver are read into its configuration.
+				if isAPIServerFile(file.Path()) {
+					continue
+				}
+
 				planConfig.Files = append(planConfig.Files, File{
 					Content:     readString(file.Content()),
 					Permissions: readInt(int(file.Permissions())),
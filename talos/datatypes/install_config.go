@@ -1,18 +1,32 @@
 package datatypes
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/talos-systems/go-blockdevice/blockdevice/util/disk"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+	"gopkg.in/yaml.v3"
 )
 
 // Data copies data from terraform state types to talos types.
 func (install InstallConfig) Data() (any, error) {
 	installConfig := &v1alpha1.InstallConfig{}
 
-	installConfig.InstallDisk = generate.DefaultGenOptions().InstallDisk
+	if install.DiskSelector == nil {
+		installConfig.InstallDisk = generate.DefaultGenOptions().InstallDisk
+	}
 	setString(install.Disk, &installConfig.InstallDisk)
 
+	if install.DiskSelector != nil {
+		selector, err := install.DiskSelector.Data()
+		if err != nil {
+			return nil, err
+		}
+		installConfig.InstallDiskSelector = selector.(*v1alpha1.InstallDiskSelector)
+	}
+
 	mkString(install.Image).set(&installConfig.InstallImage)
 
 	setBool(install.Wipe, &installConfig.InstallWipe)
@@ -31,6 +45,83 @@ func (install InstallConfig) Data() (any, error) {
 	return installConfig, nil
 }
 
+// Data copies data from terraform state types to talos types.
+func (planSelector InstallDiskSelector) Data() (any, error) {
+	selector := &v1alpha1.InstallDiskSelector{}
+
+	if !planSelector.Size.Null {
+		size, err := diskSizeMatcher(planSelector.Size.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid install disk size %q: %w", planSelector.Size.Value, err)
+		}
+		selector.Size = size
+	}
+
+	setString(planSelector.Name, &selector.Name)
+	setString(planSelector.Model, &selector.Model)
+	setString(planSelector.Serial, &selector.Serial)
+	setString(planSelector.Modalias, &selector.Modalias)
+	setString(planSelector.UUID, &selector.UUID)
+	setString(planSelector.WWID, &selector.WWID)
+	setString(planSelector.BusPath, &selector.BusPath)
+
+	if !planSelector.Type.Null {
+		diskType, err := disk.ParseType(planSelector.Type.Value)
+		if err != nil {
+			return nil, err
+		}
+		selector.Type = v1alpha1.InstallDiskType(diskType)
+	}
+
+	return selector, nil
+}
+
+// diskSizeMatcher parses a disk size condition. The condition of a matcher is only set when it is unmarshalled.
+func diskSizeMatcher(condition string) (*v1alpha1.InstallDiskSizeMatcher, error) {
+	matcher := &v1alpha1.InstallDiskSizeMatcher{}
+	if err := (&yaml.Node{Kind: yaml.ScalarNode, Value: condition}).Decode(matcher); err != nil {
+		return nil, err
+	}
+
+	return matcher, nil
+}
+
+func readInstallDiskSelector(talosSelector *v1alpha1.InstallDiskSelector) (*InstallDiskSelector, error) {
+	selector := &InstallDiskSelector{
+		Size:     types.String{Null: true},
+		Name:     types.String{Null: true},
+		Model:    types.String{Null: true},
+		Serial:   types.String{Null: true},
+		Modalias: types.String{Null: true},
+		UUID:     types.String{Null: true},
+		WWID:     types.String{Null: true},
+		Type:     types.String{Null: true},
+		BusPath:  types.String{Null: true},
+	}
+
+	if talosSelector.Size != nil {
+		condition, err := talosSelector.Size.MarshalYAML()
+		if err != nil {
+			return nil, err
+		}
+		mkString(condition.(string)).read(&selector.Size)
+	}
+
+	mkString(talosSelector.Name).read(&selector.Name)
+	mkString(talosSelector.Model).read(&selector.Model)
+	mkString(talosSelector.Serial).read(&selector.Serial)
+	mkString(talosSelector.Modalias).read(&selector.Modalias)
+	mkString(talosSelector.UUID).read(&selector.UUID)
+	mkString(talosSelector.WWID).read(&selector.WWID)
+	mkString(talosSelector.BusPath).read(&selector.BusPath)
+
+	if diskType := disk.Type(talosSelector.Type); diskType != disk.TypeUnknown {
+		selector.Type = readString(diskType.String())
+	}
+
+	return selector, nil
+}
+
 func (install InstallConfig) DataFunc() [](func(*v1alpha1.Config) error) {
 	return [](func(*v1alpha1.Config) error){
 		func(cfg *v1alpha1.Config) error {
@@ -80,7 +171,19 @@ func (talosInstallConfig TalosInstallConfig) ReadFunc() []ConfigReadFunc {
 			mkBool(talosInstallConfig.LegacyBIOSSupport()).read(&planConfig.Install.LegacyBios)
 			mkBool(talosInstallConfig.InstallWipe).read(&planConfig.Install.Wipe)
 
-			planConfig.Install.Disk = readString(talosInstallConfig.InstallDisk)
+			planConfig.Install.DiskSelector = nil
+			if talosInstallConfig.InstallDiskSelector != nil {
+				planConfig.Install.DiskSelector, err = readInstallDiskSelector(talosInstallConfig.InstallDiskSelector)
+				if err != nil {
+					return err
+				}
+
+				// The disk is looked up by the selector, no default is set.
+				planConfig.Install.Disk = types.String{Null: true}
+				mkString(talosInstallConfig.InstallDisk).read(&planConfig.Install.Disk)
+			} else {
+				planConfig.Install.Disk = readString(talosInstallConfig.InstallDisk)
+			}
 			planConfig.Install.KernelArgs = readStringList(talosInstallConfig.InstallExtraKernelArgs)
 
 			if len(talosInstallConfig.InstallExtensions) > 0 {
@@ -0,0 +1,49 @@
+package datatypes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+)
+
+func TestInstallDiskSelector(t *testing.T) {
+	none := types.String{Null: true}
+	selector := &InstallDiskSelector{
+		Size:     Wraps("> 1TB"),
+		Name:     none,
+		Model:    Wraps("WDC*"),
+		Serial:   none,
+		Modalias: none,
+		UUID:     none,
+		WWID:     none,
+		Type:     Wraps("nvme"),
+		BusPath:  none,
+	}
+
+	res, err := InstallConfig{Disk: none, DiskSelector: selector}.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	install := res.(*v1alpha1.InstallConfig)
+	if install.InstallDisk != "" {
+		t.Fatalf("expected no default install disk with a selector, got %q", install.InstallDisk)
+	}
+	if len(install.DiskMatchers()) != 3 {
+		t.Fatalf("expected a matcher for each of the selector's fields, got %d", len(install.DiskMatchers()))
+	}
+
+	read := &TalosConfig{}
+	if _, err := ApplyReadFunc(read, TalosInstallConfig{InstallConfig: install}.ReadFunc()); err != nil {
+		t.Fatal(err)
+	}
+	if !read.Install.Disk.Null || !reflect.DeepEqual(read.Install.DiskSelector, selector) {
+		t.Fatalf("expected: %v, got: %v and disk %v", selector, read.Install.DiskSelector, read.Install.Disk)
+	}
+
+	if _, err := (InstallDiskSelector{Size: Wraps("big"), Type: none}).Data(); err == nil {
+		t.Fatal("expected an invalid size to be rejected")
+	}
+}
@@ -2,12 +2,19 @@ package datatypes
 
 import (
 	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
 	"gopkg.in/yaml.v3"
 )
 
+const (
+	nodeLabelsArg = "node-labels"
+	nodeTaintsArg = "register-with-taints"
+)
+
 func (kubelet KubeletConfig) DataFunc() [](func(*v1alpha1.Config) error) {
 	funs := [](func(*v1alpha1.Config) error){
 		func(cfg *v1alpha1.Config) error {
@@ -35,6 +42,7 @@ func (kubelet KubeletConfig) DataFunc() [](func(*v1alpha1.Config) error) {
 			for k, arg := range kubelet.ExtraArgs {
 				talosKubelet.KubeletExtraArgs[k] = arg.Value
 			}
+			kubelet.setRegistration(talosKubelet)
 			for _, mount := range kubelet.ExtraMounts {
 				m, err := mount.Data()
 				if err != nil {
@@ -65,7 +73,106 @@ func (kubelet KubeletConfig) zero() bool {
 		len(kubelet.ClusterDNS) <= 0 &&
 		len(kubelet.ExtraMounts) <= 0 &&
 		len(kubelet.NodeIPValidSubnets) <= 0 &&
-		len(kubelet.ExtraArgs) <= 0
+		len(kubelet.ExtraArgs) <= 0 &&
+		len(kubelet.Labels) <= 0 &&
+		len(kubelet.Taints) <= 0
+}
+
+// setRegistration renders the labels and taints the node is registered with into the kubelet's arguments.
+func (kubelet KubeletConfig) setRegistration(talosKubelet *v1alpha1.KubeletConfig) {
+	if len(kubelet.Labels) <= 0 && len(kubelet.Taints) <= 0 {
+		return
+	}
+
+	if talosKubelet.KubeletExtraArgs == nil {
+		talosKubelet.KubeletExtraArgs = map[string]string{}
+	}
+
+	if len(kubelet.Labels) > 0 {
+		labels := make([]string, 0, len(kubelet.Labels))
+		for key, value := range kubelet.Labels {
+			labels = append(labels, key+"="+value.Value)
+		}
+		sort.Strings(labels)
+		talosKubelet.KubeletExtraArgs[nodeLabelsArg] = strings.Join(labels, ",")
+	}
+
+	if len(kubelet.Taints) > 0 {
+		taints := make([]string, 0, len(kubelet.Taints))
+		for _, taint := range kubelet.Taints {
+			t := taint.Key.Value
+			if !taint.Value.Null {
+				t += "=" + taint.Value.Value
+			}
+			taints = append(taints, t+":"+taint.Effect.Value)
+		}
+		talosKubelet.KubeletExtraArgs[nodeTaintsArg] = strings.Join(taints, ",")
+	}
+}
+
+// readRegistration parses the labels and taints the node is registered with out of the kubelet's arguments. Arguments
+// which were configured as extra arguments are kept as such.
+func (kubelet *KubeletConfig) readRegistration(prior map[string]types.String) {
+	kubelet.Labels = nil
+	if _, ok := prior[nodeLabelsArg]; !ok {
+		if labels, ok := parseLabels(kubelet.ExtraArgs[nodeLabelsArg]); ok {
+			kubelet.Labels = labels
+			delete(kubelet.ExtraArgs, nodeLabelsArg)
+		}
+	}
+
+	kubelet.Taints = nil
+	if _, ok := prior[nodeTaintsArg]; !ok {
+		if taints, ok := parseTaints(kubelet.ExtraArgs[nodeTaintsArg]); ok {
+			kubelet.Taints = taints
+			delete(kubelet.ExtraArgs, nodeTaintsArg)
+		}
+	}
+
+	if len(kubelet.ExtraArgs) <= 0 {
+		kubelet.ExtraArgs = nil
+	}
+}
+
+func parseLabels(arg types.String) (map[string]types.String, bool) {
+	if arg.Value == "" {
+		return nil, false
+	}
+
+	labels := map[string]types.String{}
+	for _, label := range strings.Split(arg.Value, ",") {
+		key, value, ok := strings.Cut(label, "=")
+		if !ok {
+			return nil, false
+		}
+		labels[key] = readString(value)
+	}
+
+	return labels, true
+}
+
+func parseTaints(arg types.String) ([]Taint, bool) {
+	if arg.Value == "" {
+		return nil, false
+	}
+
+	var taints []Taint
+	for _, t := range strings.Split(arg.Value, ",") {
+		keyValue, effect, ok := strings.Cut(t, ":")
+		if !ok {
+			return nil, false
+		}
+
+		taint := Taint{Value: types.String{Null: true}, Effect: readString(effect)}
+		key, value, ok := strings.Cut(keyValue, "=")
+		taint.Key = readString(key)
+		if ok {
+			taint.Value = readString(value)
+		}
+		taints = append(taints, taint)
+	}
+
+	return taints, true
 }
 
 // Data copies data from terraform state types to talos types.
@@ -94,6 +201,7 @@ func (kubelet KubeletConfig) Data() (interface{}, error) {
 	for k, arg := range kubelet.ExtraArgs {
 		talosKubelet.KubeletExtraArgs[k] = arg.Value
 	}
+	kubelet.setRegistration(talosKubelet)
 	for _, mount := range kubelet.ExtraMounts {
 		m, err := mount.Data()
 		if err != nil {
@@ -184,7 +292,9 @@ func (talosKubelet TalosKubelet) ReadFunc() []ConfigReadFunc {
 			}
 
 			planConfig.Kubelet.ClusterDNS = readStringList(talosKubelet.ClusterDNS())
+			prior := planConfig.Kubelet.ExtraArgs
 			planConfig.Kubelet.ExtraArgs = readStringMap(talosKubelet.ExtraArgs())
+			planConfig.Kubelet.readRegistration(prior)
 			planConfig.Kubelet.NodeIPValidSubnets = readStringList(talosKubelet.NodeIP().ValidSubnets())
 
 			if planConfig.Kubelet.zero() {
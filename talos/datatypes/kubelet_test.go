@@ -0,0 +1,55 @@
+package datatypes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+)
+
+func TestKubeletRegistration(t *testing.T) {
+	kubelet := KubeletConfig{
+		Image:     Wraps((&v1alpha1.KubeletConfig{}).Image()),
+		ExtraArgs: map[string]types.String{"rotate-server-certificates": Wraps("true")},
+		Labels:    map[string]types.String{"zone": Wraps("eu-west-1a"), "node-role.kubernetes.io/storage": Wraps("")},
+		Taints: []Taint{
+			{Key: Wraps("dedicated"), Value: Wraps("storage"), Effect: Wraps("NoSchedule")},
+			{Key: Wraps("gpu"), Value: types.String{Null: true}, Effect: Wraps("NoExecute")},
+		},
+	}
+
+	res, err := kubelet.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	talosKubelet := res.(*v1alpha1.KubeletConfig)
+	expected := map[string]string{
+		"rotate-server-certificates": "true",
+		"node-labels":                "node-role.kubernetes.io/storage=,zone=eu-west-1a",
+		"register-with-taints":       "dedicated=storage:NoSchedule,gpu:NoExecute",
+	}
+	if !reflect.DeepEqual(talosKubelet.KubeletExtraArgs, expected) {
+		t.Fatalf("expected: %v, got: %v", expected, talosKubelet.KubeletExtraArgs)
+	}
+
+	read := &TalosConfig{}
+	if _, err := ApplyReadFunc(read, TalosKubelet{KubeletConfig: talosKubelet}.ReadFunc()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read.Kubelet.ExtraArgs, kubelet.ExtraArgs) || !reflect.DeepEqual(read.Kubelet.Labels, kubelet.Labels) ||
+		!reflect.DeepEqual(read.Kubelet.Taints, kubelet.Taints) {
+		t.Fatalf("expected: %v, got: %v", kubelet, *read.Kubelet)
+	}
+
+	// Labels configured as an extra argument are kept as one.
+	read = &TalosConfig{Kubelet: &KubeletConfig{ExtraArgs: map[string]types.String{"node-labels": Wraps("zone=a")}}}
+	talosKubelet = &v1alpha1.KubeletConfig{KubeletExtraArgs: map[string]string{"node-labels": "zone=a"}}
+	if _, err := ApplyReadFunc(read, TalosKubelet{KubeletConfig: talosKubelet}.ReadFunc()); err != nil {
+		t.Fatal(err)
+	}
+	if read.Kubelet.Labels != nil || read.Kubelet.ExtraArgs["node-labels"].Value != "zone=a" {
+		t.Fatalf("expected the node-labels argument to be kept, got: %v", *read.Kubelet)
+	}
+}
@@ -0,0 +1,288 @@
+package datatypes
+
+import (
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// This file contains the normalisation applied when a node's live configuration is read back into state.
+// Talos fills in defaults, such as component images, the install image and admission plugin configuration, which the
+// user never set. Values only differing by such defaults are not drift and must not show up in a plan.
+
+var (
+	stringType = reflect.TypeOf(types.String{})
+	boolType   = reflect.TypeOf(types.Bool{})
+	int64Type  = reflect.TypeOf(types.Int64{})
+)
+
+// Copy returns a deep copy of a plan value, so that it can be kept while readers update the original in place.
+func Copy[T any](in T) T {
+	out := reflect.New(reflect.TypeOf(&in).Elem()).Elem()
+	copyValue(reflect.ValueOf(&in).Elem(), out)
+
+	return out.Interface().(T)
+}
+
+func copyValue(in, out reflect.Value) {
+	switch in.Kind() {
+	case reflect.Pointer:
+		if in.IsNil() {
+			return
+		}
+		out.Set(reflect.New(in.Type().Elem()))
+		copyValue(in.Elem(), out.Elem())
+	case reflect.Struct:
+		if isPrimitive(in.Type()) {
+			out.Set(in)
+			return
+		}
+		for i := 0; i < in.NumField(); i++ {
+			if out.Field(i).CanSet() {
+				copyValue(in.Field(i), out.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if in.IsNil() {
+			return
+		}
+		out.Set(reflect.MakeSlice(in.Type(), in.Len(), in.Len()))
+		for i := 0; i < in.Len(); i++ {
+			copyValue(in.Index(i), out.Index(i))
+		}
+	case reflect.Map:
+		if in.IsNil() {
+			return
+		}
+		out.Set(reflect.MakeMapWithSize(in.Type(), in.Len()))
+		for _, key := range in.MapKeys() {
+			value := reflect.New(in.Type().Elem()).Elem()
+			copyValue(in.MapIndex(key), value)
+			out.SetMapIndex(key, value)
+		}
+	default:
+		out.Set(in)
+	}
+}
+
+// Normalize reconciles the prior state of a plan value with the value read from a node. defaults holds what Talos
+// generates when nothing is configured, read the same way as live.
+//
+// Where the live value matches the prior state, or both only hold a default or nothing at all, the prior state is
+// kept. Anything else is a change made outside of Terraform and the live value is returned.
+func Normalize[T any](prior, live, defaults T) T {
+	out := reflect.New(reflect.TypeOf(&prior).Elem()).Elem()
+	out.Set(normalizeValue(
+		reflect.ValueOf(&prior).Elem(),
+		reflect.ValueOf(&live).Elem(),
+		reflect.ValueOf(&defaults).Elem(),
+	))
+
+	return out.Interface().(T)
+}
+
+func normalizeValue(prior, live, def reflect.Value) reflect.Value {
+	if equivalent(prior, live) {
+		return prior
+	}
+
+	if defaultOrUnset(prior, def) && defaultOrUnset(live, def) {
+		return prior
+	}
+
+	switch prior.Kind() {
+	case reflect.Pointer:
+		// Blocks read as nil while the state holds user values are compared field by field against a block
+		// with nothing set, so that only the fields Talos actually dropped are reported.
+		elem := prior.Type().Elem()
+		out := reflect.New(elem)
+		out.Elem().Set(normalizeValue(deref(prior, elem), deref(live, elem), deref(def, elem)))
+
+		if (prior.IsNil() || live.IsNil()) && unset(out) {
+			return reflect.Zero(prior.Type())
+		}
+		return out
+	case reflect.Struct:
+		if isPrimitive(prior.Type()) {
+			return live
+		}
+
+		out := reflect.New(prior.Type()).Elem()
+		for i := 0; i < prior.NumField(); i++ {
+			if !out.Field(i).CanSet() {
+				continue
+			}
+			out.Field(i).Set(normalizeValue(prior.Field(i), live.Field(i), def.Field(i)))
+		}
+		return out
+	case reflect.Slice:
+		if prior.Len() != live.Len() {
+			return live
+		}
+
+		out := reflect.MakeSlice(prior.Type(), prior.Len(), prior.Len())
+		for i := 0; i < prior.Len(); i++ {
+			out.Index(i).Set(normalizeValue(prior.Index(i), live.Index(i), index(def, i, prior.Type().Elem())))
+		}
+		return out
+	case reflect.Map:
+		// Entries are normalised one by one, so a default entry Talos adds to a map, such as a sysctl, doesn't
+		// hide changes to the entries the user set.
+		elem := prior.Type().Elem()
+		out := reflect.MakeMapWithSize(prior.Type(), live.Len())
+		for _, key := range append(prior.MapKeys(), live.MapKeys()...) {
+			p, l := prior.MapIndex(key), live.MapIndex(key)
+			value := normalizeValue(mapIndex(prior, key, elem), mapIndex(live, key, elem), mapIndex(def, key, elem))
+
+			if (p.IsValid() && l.IsValid()) || !unset(value) || (p.IsValid() && unset(p)) {
+				out.SetMapIndex(key, value)
+			}
+		}
+
+		if out.Len() == 0 && prior.IsNil() {
+			return prior
+		}
+		return out
+	default:
+		return live
+	}
+}
+
+// equivalent reports whether two plan values hold the same values, treating null and empty values alike.
+func equivalent(a, b reflect.Value) bool {
+	if unset(a) || unset(b) {
+		return unset(a) && unset(b)
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer:
+		return equivalent(a.Elem(), b.Elem())
+	case reflect.Struct:
+		switch a.Type() {
+		case stringType:
+			return a.Interface().(types.String).Value == b.Interface().(types.String).Value
+		case boolType:
+			return a.Interface().(types.Bool).Value == b.Interface().(types.Bool).Value
+		case int64Type:
+			return a.Interface().(types.Int64).Value == b.Interface().(types.Int64).Value
+		}
+
+		for i := 0; i < a.NumField(); i++ {
+			if a.Type().Field(i).IsExported() && !equivalent(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equivalent(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for _, key := range a.MapKeys() {
+			if v := b.MapIndex(key); !v.IsValid() || !equivalent(a.MapIndex(key), v) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+}
+
+// unset reports whether a plan value holds nothing, a null or empty primitive, an empty collection or a block
+// without any value set.
+func unset(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer:
+		return v.IsNil() || unset(v.Elem())
+	case reflect.Struct:
+		switch v.Type() {
+		case stringType:
+			s := v.Interface().(types.String)
+			return s.Null || (!s.Unknown && s.Value == "")
+		case boolType:
+			b := v.Interface().(types.Bool)
+			return b.Null || (!b.Unknown && !b.Value)
+		case int64Type:
+			i := v.Interface().(types.Int64)
+			return i.Null || (!i.Unknown && i.Value == 0)
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() && !unset(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+// defaultOrUnset reports whether a plan value holds nothing or only Talos defaults. Blocks and collections are
+// compared value by value so a block holding a mix of defaults and unset values qualifies.
+func defaultOrUnset(v, def reflect.Value) bool {
+	if unset(v) || equivalent(v, def) {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		return defaultOrUnset(v.Elem(), deref(def, v.Type().Elem()))
+	case reflect.Struct:
+		if isPrimitive(v.Type()) {
+			return false
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() && !defaultOrUnset(v.Field(i), def.Field(i)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func isPrimitive(t reflect.Type) bool {
+	return t == stringType || t == boolType || t == int64Type
+}
+
+// deref returns what a possibly nil pointer points to, or the zero value of t.
+func deref(v reflect.Value, t reflect.Type) reflect.Value {
+	if v.IsNil() {
+		return reflect.New(t).Elem()
+	}
+
+	return v.Elem()
+}
+
+// index returns the element i of a slice, or the zero value of t if it is out of range.
+func index(v reflect.Value, i int, t reflect.Type) reflect.Value {
+	if i >= v.Len() {
+		return reflect.New(t).Elem()
+	}
+
+	return v.Index(i)
+}
+
+// mapIndex returns the element at key in a map, or the zero value of t if there is none.
+func mapIndex(v reflect.Value, key reflect.Value, t reflect.Type) reflect.Value {
+	if e := v.MapIndex(key); e.IsValid() {
+		return e
+	}
+
+	return reflect.New(t).Elem()
+}
@@ -0,0 +1,92 @@
+package datatypes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNormalize(t *testing.T) {
+	defaults := TalosConfig{
+		APIServer: &APIServerConfig{
+			Image:      Wraps("k8s.gcr.io/kube-apiserver:v1.24.2"),
+			CertSANS:   Wrapsl("10.0.0.1"),
+			DisablePSP: Wrapb(true),
+		},
+		Install: &InstallConfig{
+			Image: Wraps("ghcr.io/siderolabs/installer:v1.1.1"),
+		},
+	}
+
+	tests := map[string]struct {
+		prior, live, output TalosConfig
+	}{
+		"unset matches default": {
+			prior:  TalosConfig{},
+			live:   Copy(defaults),
+			output: TalosConfig{},
+		},
+		"null attribute matches default": {
+			prior: TalosConfig{
+				Install: &InstallConfig{Image: types.String{Null: true}, Disk: Wraps("/dev/sda")},
+			},
+			live: TalosConfig{
+				Install: &InstallConfig{Image: Wraps("ghcr.io/siderolabs/installer:v1.1.1"), Disk: Wraps("/dev/sda")},
+			},
+			output: TalosConfig{
+				Install: &InstallConfig{Image: types.String{Null: true}, Disk: Wraps("/dev/sda")},
+			},
+		},
+		"default kept when block is not read": {
+			prior: TalosConfig{
+				APIServer: &APIServerConfig{Image: Wraps("k8s.gcr.io/kube-apiserver:v1.24.2")},
+			},
+			live: TalosConfig{},
+			output: TalosConfig{
+				APIServer: &APIServerConfig{Image: Wraps("k8s.gcr.io/kube-apiserver:v1.24.2")},
+			},
+		},
+		"out of band change": {
+			prior: TalosConfig{
+				APIServer: &APIServerConfig{Image: types.String{Null: true}, Env: map[string]types.String{"A": Wraps("1")}},
+			},
+			live: TalosConfig{
+				APIServer: &APIServerConfig{Image: Wraps("k8s.gcr.io/kube-apiserver:v1.24.3"), Env: map[string]types.String{"A": Wraps("2")}},
+			},
+			output: TalosConfig{
+				APIServer: &APIServerConfig{Image: Wraps("k8s.gcr.io/kube-apiserver:v1.24.3"), Env: map[string]types.String{"A": Wraps("2")}},
+			},
+		},
+		"removed out of band": {
+			prior: TalosConfig{
+				Install: &InstallConfig{Disk: Wraps("/dev/sda")},
+			},
+			live:   TalosConfig{},
+			output: TalosConfig{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := Normalize(tc.prior, tc.live, defaults)
+			if !reflect.DeepEqual(out, tc.output) {
+				t.Fatalf("expected: %+v, got: %+v", tc.output, out)
+			}
+		})
+	}
+}
+
+func TestCopy(t *testing.T) {
+	in := TalosConfig{
+		APIServer: &APIServerConfig{CertSANS: Wrapsl("10.0.0.1"), Env: map[string]types.String{"A": Wraps("1")}},
+	}
+
+	out := Copy(in)
+	out.APIServer.CertSANS[0] = Wraps("10.0.0.2")
+	out.APIServer.Env["A"] = Wraps("2")
+
+	if in.APIServer.CertSANS[0].Value != "10.0.0.1" || in.APIServer.Env["A"].Value != "1" {
+		t.Fatalf("copy shares values with the original: %+v", in.APIServer)
+	}
+}
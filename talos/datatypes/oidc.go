@@ -0,0 +1,65 @@
+package datatypes
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	oidcIssuerURLArg = "oidc-issuer-url"
+	oidcCAArg        = "oidc-ca-file"
+	oidcCAPath       = apiServerConfigDir + "/oidc-ca.crt"
+)
+
+// args maps the apiserver arguments rendered from the OIDC settings, except for the CA's, to their attributes.
+func (oidc *OIDCConfig) args() map[string]*types.String {
+	return map[string]*types.String{
+		oidcIssuerURLArg:       &oidc.IssuerURL,
+		"oidc-client-id":       &oidc.ClientID,
+		"oidc-username-claim":  &oidc.UsernameClaim,
+		"oidc-username-prefix": &oidc.UsernamePrefix,
+		"oidc-groups-claim":    &oidc.GroupsClaim,
+		"oidc-groups-prefix":   &oidc.GroupsPrefix,
+	}
+}
+
+// Data renders the apiserver arguments of the OIDC settings.
+func (planOIDC OIDCConfig) Data() (any, error) {
+	args := map[string]string{}
+	for arg, value := range planOIDC.args() {
+		if !value.Null {
+			args[arg] = value.Value
+		}
+	}
+
+	if !planOIDC.CA.Null {
+		args[oidcCAArg] = oidcCAPath
+	}
+
+	return args, nil
+}
+
+// readOIDC parses the OIDC settings out of the apiserver's arguments and removes them from args. The CA is read from
+// files when it's the one rendered by the provider.
+func readOIDC(args map[string]types.String, files Files) (*OIDCConfig, bool) {
+	if _, ok := args[oidcIssuerURLArg]; !ok {
+		return nil, false
+	}
+
+	oidc := &OIDCConfig{CA: types.String{Null: true}}
+	for arg, value := range oidc.args() {
+		*value = types.String{Null: true}
+		if v, ok := args[arg]; ok {
+			*value = v
+			delete(args, arg)
+		}
+	}
+
+	if args[oidcCAArg].Value == oidcCAPath {
+		if ca, ok := readAPIServerFile(files, oidcCAPath); ok {
+			oidc.CA = readString(ca)
+			delete(args, oidcCAArg)
+		}
+	}
+
+	return oidc, true
+}
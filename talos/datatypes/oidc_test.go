@@ -0,0 +1,72 @@
+package datatypes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+)
+
+// TestAPIServerAuthentication checks that the audit policy and OIDC settings are rendered into the apiserver's
+// arguments, mounts and the node's machine files, and read back from them.
+func TestAPIServerAuthentication(t *testing.T) {
+	apiServer := APIServerConfig{
+		ExtraArgs: map[string]types.String{"oidc-required-claim": Wraps("hd=example.com")},
+		AuditPolicy: &AuditPolicyConfig{
+			Rules:  []AuditRule{{Level: Wraps("Metadata")}},
+			Policy: types.String{Null: true},
+		},
+		OIDC: &OIDCConfig{
+			IssuerURL:      Wraps("https://accounts.example.com"),
+			ClientID:       Wraps("kubernetes"),
+			UsernameClaim:  Wraps("email"),
+			UsernamePrefix: types.String{Null: true},
+			GroupsClaim:    Wraps("groups"),
+			GroupsPrefix:   Wraps("oidc:"),
+			CA:             Wraps(string(InputBundleExample.Certs.Etcd.Crt)),
+		},
+	}
+
+	cfg := &v1alpha1.Config{MachineConfig: &v1alpha1.MachineConfig{}, ClusterConfig: &v1alpha1.ClusterConfig{}}
+	if err := ApplyDataFunc(cfg, apiServer.DataFunc()); err != nil {
+		t.Fatal(err)
+	}
+
+	talosAPIServer := cfg.ClusterConfig.APIServerConfig
+	if talosAPIServer.ExtraArgsConfig["oidc-ca-file"] != oidcCAPath || talosAPIServer.ExtraArgsConfig[auditPolicyArg] != auditPolicyPath {
+		t.Fatalf("expected the arguments to refer to the rendered files, got %v", talosAPIServer.ExtraArgsConfig)
+	}
+	if len(talosAPIServer.ExtraVolumesConfig) != 1 || len(cfg.MachineConfig.MachineFiles) != 2 {
+		t.Fatalf("expected the rendered files to be mounted, got mounts %v and files %v", talosAPIServer.ExtraVolumesConfig, cfg.MachineConfig.MachineFiles)
+	}
+
+	read := &TalosConfig{}
+	readFuncs := AppendReadFunc(nil,
+		TalosAPIServerConfig{APIServerConfig: talosAPIServer, Files: cfg.MachineConfig.MachineFiles},
+		TalosFiles{Files: cfg.MachineConfig.MachineFiles},
+	)
+	if _, err := ApplyReadFunc(read, readFuncs); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(read.APIServer.AuditPolicy, apiServer.AuditPolicy) || !reflect.DeepEqual(read.APIServer.OIDC, apiServer.OIDC) {
+		t.Fatalf("expected: %v and %v, got: %v and %v", apiServer.AuditPolicy, apiServer.OIDC, read.APIServer.AuditPolicy, read.APIServer.OIDC)
+	}
+	if !reflect.DeepEqual(read.APIServer.ExtraArgs, apiServer.ExtraArgs) {
+		t.Errorf("expected extra arguments %v, got %v", apiServer.ExtraArgs, read.APIServer.ExtraArgs)
+	}
+	if read.APIServer.ExtraVolumes != nil || read.Files != nil {
+		t.Errorf("expected the rendered files and their mount not to be read, got %v and %v", read.APIServer.ExtraVolumes, read.Files)
+	}
+
+	// OIDC settings configured as extra arguments are kept as such.
+	prior := map[string]types.String{oidcIssuerURLArg: Wraps("https://accounts.example.com")}
+	read = &TalosConfig{APIServer: &APIServerConfig{ExtraArgs: prior}}
+	if _, err := ApplyReadFunc(read, TalosAPIServerConfig{APIServerConfig: talosAPIServer}.ReadFunc()); err != nil {
+		t.Fatal(err)
+	}
+	if read.APIServer.OIDC != nil || read.APIServer.ExtraArgs[oidcIssuerURLArg].Value != "https://accounts.example.com" {
+		t.Fatalf("expected the OIDC arguments to be kept, got %v", *read.APIServer)
+	}
+}
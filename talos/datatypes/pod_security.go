@@ -0,0 +1,127 @@
+package datatypes
+
+import (
+	"bytes"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	podSecurityPlugin     = "PodSecurity"
+	podSecurityAPIVersion = "pod-security.admission.config.k8s.io/v1alpha1"
+	podSecurityKind       = "PodSecurityConfiguration"
+)
+
+// podSecurityConfiguration is the configuration of the PodSecurity admission plugin.
+type podSecurityConfiguration struct {
+	APIVersion string                `yaml:"apiVersion"`
+	Kind       string                `yaml:"kind"`
+	Defaults   podSecurityDefaults   `yaml:"defaults"`
+	Exemptions podSecurityExemptions `yaml:"exemptions"`
+}
+
+type podSecurityDefaults struct {
+	Enforce        string `yaml:"enforce,omitempty"`
+	EnforceVersion string `yaml:"enforce-version,omitempty"`
+	Audit          string `yaml:"audit,omitempty"`
+	AuditVersion   string `yaml:"audit-version,omitempty"`
+	Warn           string `yaml:"warn,omitempty"`
+	WarnVersion    string `yaml:"warn-version,omitempty"`
+}
+
+type podSecurityExemptions struct {
+	Namespaces     []string `yaml:"namespaces"`
+	RuntimeClasses []string `yaml:"runtimeClasses"`
+	Usernames      []string `yaml:"usernames"`
+}
+
+// PodSecurityDefaults returns the Pod Security settings used when no admission plugins are configured.
+func PodSecurityDefaults() *PodSecurityConfig {
+	return &PodSecurityConfig{
+		Enforce:          types.String{Value: "baseline"},
+		EnforceVersion:   types.String{Value: "latest"},
+		Audit:            types.String{Value: "restricted"},
+		AuditVersion:     types.String{Value: "latest"},
+		Warn:             types.String{Value: "restricted"},
+		WarnVersion:      types.String{Value: "latest"},
+		ExemptNamespaces: []types.String{{Value: "kube-system"}},
+	}
+}
+
+// Data copies data from terraform state types to talos types.
+func (planPodSecurity PodSecurityConfig) Data() (any, error) {
+	config := podSecurityConfiguration{
+		APIVersion: podSecurityAPIVersion,
+		Kind:       podSecurityKind,
+	}
+
+	setString(planPodSecurity.Enforce, &config.Defaults.Enforce)
+	setString(planPodSecurity.EnforceVersion, &config.Defaults.EnforceVersion)
+	setString(planPodSecurity.Audit, &config.Defaults.Audit)
+	setString(planPodSecurity.AuditVersion, &config.Defaults.AuditVersion)
+	setString(planPodSecurity.Warn, &config.Defaults.Warn)
+	setString(planPodSecurity.WarnVersion, &config.Defaults.WarnVersion)
+
+	setStringList(planPodSecurity.ExemptNamespaces, &config.Exemptions.Namespaces)
+	setStringList(planPodSecurity.ExemptRuntimeClasses, &config.Exemptions.RuntimeClasses)
+	setStringList(planPodSecurity.ExemptUsernames, &config.Exemptions.Usernames)
+
+	out, err := yaml.Marshal(&config)
+	if err != nil {
+		return nil, err
+	}
+
+	var plugin v1alpha1.Unstructured
+	if err := yaml.Unmarshal(out, &plugin); err != nil {
+		return nil, err
+	}
+
+	return &v1alpha1.AdmissionPluginConfig{
+		PluginName:          podSecurityPlugin,
+		PluginConfiguration: plugin,
+	}, nil
+}
+
+// readPodSecurity reads the configuration of the PodSecurity admission plugin. Configurations which can't be
+// represented by PodSecurityConfig, such as those of other API versions, aren't read.
+func readPodSecurity(talosPlugin *v1alpha1.AdmissionPluginConfig) (*PodSecurityConfig, bool) {
+	if talosPlugin.Name() != podSecurityPlugin {
+		return nil, false
+	}
+
+	out, err := yaml.Marshal(&talosPlugin.PluginConfiguration)
+	if err != nil {
+		return nil, false
+	}
+
+	var config podSecurityConfiguration
+	decoder := yaml.NewDecoder(bytes.NewReader(out))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil || config.APIVersion != podSecurityAPIVersion || config.Kind != podSecurityKind {
+		return nil, false
+	}
+
+	podSecurity := &PodSecurityConfig{
+		Enforce:        types.String{Null: true},
+		EnforceVersion: types.String{Null: true},
+		Audit:          types.String{Null: true},
+		AuditVersion:   types.String{Null: true},
+		Warn:           types.String{Null: true},
+		WarnVersion:    types.String{Null: true},
+	}
+
+	mkString(config.Defaults.Enforce).read(&podSecurity.Enforce)
+	mkString(config.Defaults.EnforceVersion).read(&podSecurity.EnforceVersion)
+	mkString(config.Defaults.Audit).read(&podSecurity.Audit)
+	mkString(config.Defaults.AuditVersion).read(&podSecurity.AuditVersion)
+	mkString(config.Defaults.Warn).read(&podSecurity.Warn)
+	mkString(config.Defaults.WarnVersion).read(&podSecurity.WarnVersion)
+
+	podSecurity.ExemptNamespaces = readStringList(config.Exemptions.Namespaces)
+	podSecurity.ExemptRuntimeClasses = readStringList(config.Exemptions.RuntimeClasses)
+	podSecurity.ExemptUsernames = readStringList(config.Exemptions.Usernames)
+
+	return podSecurity, true
+}
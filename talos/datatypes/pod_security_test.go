@@ -0,0 +1,92 @@
+package datatypes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
+)
+
+func TestPodSecurity(t *testing.T) {
+	apiServer := APIServerConfig{
+		AdmissionPlugins: []AdmissionPluginConfig{{Name: Wraps("EventRateLimit"), Configuration: Wraps("kind: Configuration\n")}},
+		PodSecurity:      PodSecurityDefaults(),
+	}
+
+	res, err := apiServer.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plugins := res.(*v1alpha1.APIServerConfig).AdmissionControlConfig
+	if len(plugins) != 2 || plugins[1].Name() != "PodSecurity" {
+		t.Fatalf("expected the PodSecurity plugin after the configured ones, got %v", plugins)
+	}
+
+	read := &TalosConfig{APIServer: &APIServerConfig{AdmissionPlugins: []AdmissionPluginConfig{}}}
+	if _, err := ApplyReadFunc(read, TalosAdmissionPluginConfigs{AdmissionControlConfigs: plugins}.ReadFunc()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read.APIServer.PodSecurity, apiServer.PodSecurity) {
+		t.Fatalf("expected: %v, got: %v", apiServer.PodSecurity, read.APIServer.PodSecurity)
+	}
+	if len(read.APIServer.AdmissionPlugins) != 1 || read.APIServer.AdmissionPlugins[0].Name.Value != "EventRateLimit" {
+		t.Fatalf("expected the other plugins to be kept, got %v", read.APIServer.AdmissionPlugins)
+	}
+
+	// A PodSecurity plugin configured as an admission plugin is kept as one.
+	read = &TalosConfig{APIServer: &APIServerConfig{AdmissionPlugins: []AdmissionPluginConfig{{Name: Wraps("PodSecurity")}}}}
+	if _, err := ApplyReadFunc(read, TalosAdmissionPluginConfigs{AdmissionControlConfigs: plugins[1:]}.ReadFunc()); err != nil {
+		t.Fatal(err)
+	}
+	if read.APIServer.PodSecurity != nil || len(read.APIServer.AdmissionPlugins) != 1 {
+		t.Fatalf("expected the PodSecurity admission plugin to be kept, got %v", *read.APIServer)
+	}
+}
+
+// TestPodSecurityGenerated checks that the PodSecurity configuration Talos generates is read into pod_security.
+func TestPodSecurityGenerated(t *testing.T) {
+	cfg, err := generate.Config(machine.TypeControlPlane, &InputBundleExample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plugins := cfg.ClusterConfig.APIServerConfig.AdmissionControlConfig
+	if len(plugins) == 0 {
+		t.Fatal("expected Talos to generate the PodSecurity plugin")
+	}
+
+	for _, plugin := range plugins {
+		podSecurity, ok := readPodSecurity(plugin)
+		if !ok {
+			t.Fatalf("unable to read the generated %s plugin", plugin.Name())
+		}
+		if !reflect.DeepEqual(podSecurity, PodSecurityDefaults()) {
+			t.Fatalf("expected: %v, got: %v", PodSecurityDefaults(), podSecurity)
+		}
+	}
+}
+
+func TestPodSecurityOtherVersion(t *testing.T) {
+	res, err := AdmissionPluginConfig{Name: Wraps("PodSecurity"), Configuration: Wraps(`apiVersion: pod-security.admission.config.k8s.io/v1beta1
+kind: PodSecurityConfiguration
+defaults:
+    enforce: restricted
+`)}.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := readPodSecurity(res.(*v1alpha1.AdmissionPluginConfig)); ok {
+		t.Fatal("expected a configuration of another API version not to be read")
+	}
+}
+
+func TestAdmissionPluginInvalidYAML(t *testing.T) {
+	if _, err := (AdmissionPluginConfig{Name: Wraps("EventRateLimit"), Configuration: types.String{Value: "kind: ["}}).Data(); err == nil {
+		t.Fatal("expected malformed YAML to be rejected")
+	}
+}
@@ -98,7 +98,11 @@ var TalosConfigSchema = tfsdk.Schema{
 			Description: EncryptionSchema.MarkdownDescription,
 			Attributes:  tfsdk.SingleNestedAttributes(EncryptionSchema.Attributes),
 		},
-		// features not implemented
+		"features": {
+			Optional:    true,
+			Description: FeaturesSchema.Description,
+			Attributes:  tfsdk.SingleNestedAttributes(FeaturesSchema.Attributes),
+		},
 		"udev": {
 			Type: types.ListType{
 				ElemType: types.StringType,
@@ -323,16 +327,20 @@ var KubeletConfigSchema tfsdk.Schema = tfsdk.Schema{
 			PlanModifiers: tfsdk.AttributePlanModifiers{
 				tfsdk.UseStateForUnknown(),
 			},
-			Description: "An optional reference to an alternative kubelet image.",
-			//			ValidateFunc: validateImage,
+			Description: "An optional reference to an alternative kubelet image. Defaults to the image of the base configuration's `kubernetes_version`.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateImage(),
+			},
 		},
-		// TODO: Add validator for IP
 		"cluster_dns": {
 			Type: types.ListType{
 				ElemType: types.StringType,
 			},
 			Description: "An optional reference to an alternative kubelet clusterDNS ip list.",
 			Optional:    true,
+			Validators: []tfsdk.AttributeValidator{
+				ValidateIP(),
+			},
 		},
 		"extra_args": {
 			Type: types.MapType{
@@ -357,13 +365,62 @@ var KubeletConfigSchema tfsdk.Schema = tfsdk.Schema{
 			Optional:    true,
 			Description: "Used to force kubelet to use the node FQDN for registration. This is required in clouds like AWS.",
 		},
-		// TODO: Add validator
 		"node_ip_valid_subnets": {
 			Type: types.ListType{
 				ElemType: types.StringType,
 			},
 			Optional:    true,
 			Description: "The validSubnets field configures the networks to pick kubelet node IP from.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateSubnet(),
+			},
+		},
+		"labels": {
+			Type: types.MapType{
+				ElemType: types.StringType,
+			},
+			Optional:    true,
+			Description: "Labels the node is registered with. Conflicts with the node-labels extra argument.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateLabelKeys(),
+				ValidateLabelValue(),
+			},
+		},
+		"taints": {
+			Optional:    true,
+			Description: TaintSchema.Description,
+			Attributes:  tfsdk.ListNestedAttributes(TaintSchema.Attributes),
+		},
+	},
+}
+
+// TaintSchema describes a taint the node is registered with.
+var TaintSchema tfsdk.Schema = tfsdk.Schema{
+	Description: "Taints the node is registered with. Conflicts with the register-with-taints extra argument.",
+	Attributes: map[string]tfsdk.Attribute{
+		"key": {
+			Type:        types.StringType,
+			Required:    true,
+			Description: "The taint's key.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateLabelKey(),
+			},
+		},
+		"value": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "The taint's value.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateLabelValue(),
+			},
+		},
+		"effect": {
+			Type:        types.StringType,
+			Required:    true,
+			Description: "The taint's effect, one of NoSchedule, PreferNoSchedule or NoExecute.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateTaintEffect(),
+			},
 		},
 	},
 }
@@ -381,6 +438,9 @@ var CNISchema tfsdk.Schema = tfsdk.Schema{
 			Type: types.ListType{
 				ElemType: types.StringType,
 			},
+			Validators: []tfsdk.AttributeValidator{
+				ValidateURL("http", "https"),
+			},
 		},
 	},
 }
@@ -402,6 +462,19 @@ var NetworkKubeSpanSchema tfsdk.Schema = tfsdk.Schema{
 	},
 }
 
+// FeaturesSchema describes the machine's Talos features.
+var FeaturesSchema tfsdk.Schema = tfsdk.Schema{
+	Description: "Describes the machine's Talos features.",
+	Attributes: map[string]tfsdk.Attribute{
+		"rbac": {
+			Optional: true,
+			Type:     types.BoolType,
+			Description: "Enable role-based access control (RBAC) for the Talos API, so that talosconfigs with restricted roles can be " +
+				"handed out. Defaults to enabled for configurations targeting Talos v0.11 and later.",
+		},
+	},
+}
+
 // RegistrySchema represents the image pull options.
 var RegistrySchema tfsdk.Schema = tfsdk.Schema{
 	Description: "Represents the image pull options.",
@@ -490,6 +563,9 @@ var NetworkConfigSchema = tfsdk.Schema{
 			Type:        types.StringType,
 			Optional:    true,
 			Description: "Used to statically set the hostname for the machine.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateDomain(),
+			},
 		},
 		"devices": {
 			Optional:    true,
@@ -502,6 +578,9 @@ var NetworkConfigSchema = tfsdk.Schema{
 			},
 			Optional:    true,
 			Description: "Used to statically set the nameservers for the machine.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateIP(),
+			},
 		},
 		"extra_hosts": {
 			Type: types.MapType{
@@ -520,6 +599,34 @@ var NetworkConfigSchema = tfsdk.Schema{
 	},
 }
 
+// DeviceSelectorSchema selects a network device by its hardware.
+var DeviceSelectorSchema tfsdk.Schema = tfsdk.Schema{
+	Description: "Selects the network device by its hardware instead of its interface name, for hardware where the same port is " +
+		"named differently. All set fields must match, each supports matching by wildcard.",
+	Attributes: map[string]tfsdk.Attribute{
+		"bus_path": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "PCI or USB bus prefix, such as 00:*.",
+		},
+		"hardware_addr": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "Device hardware address, such as *:f0:ab.",
+		},
+		"pci_id": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "PCI ID (vendor ID, product ID), such as 1AF4:1000.",
+		},
+		"driver": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "Kernel driver, such as virtio_net.",
+		},
+	},
+}
+
 // NetworkDeviceSchema describes a Talos Device configuration.
 var NetworkDeviceSchema tfsdk.Schema = tfsdk.Schema{
 	Description:         "Describes a Talos network device configuration. The map's key is the interface name.",
@@ -527,8 +634,13 @@ var NetworkDeviceSchema tfsdk.Schema = tfsdk.Schema{
 	Attributes: map[string]tfsdk.Attribute{
 		"name": {
 			Type:        types.StringType,
-			Required:    true,
-			Description: "Network device's Linux interface name.",
+			Optional:    true,
+			Description: "Network device's Linux interface name. Either name or device_selector must be set.",
+		},
+		"device_selector": {
+			Optional:    true,
+			Attributes:  tfsdk.SingleNestedAttributes(DeviceSelectorSchema.Attributes),
+			Description: DeviceSelectorSchema.Description,
 		},
 		"addresses": {
 			Type: types.ListType{
@@ -536,7 +648,9 @@ var NetworkDeviceSchema tfsdk.Schema = tfsdk.Schema{
 			},
 			Required:    true,
 			Description: "A list of IP addresses for the interface.",
-			// TODO Add field validation
+			Validators: []tfsdk.AttributeValidator{
+				ValidateCIDR(),
+			},
 		},
 		"routes": {
 			Optional:    true,
@@ -608,6 +722,9 @@ var BondSchema tfsdk.Schema = tfsdk.Schema{
 			Type: types.ListType{
 				ElemType: types.StringType,
 			},
+			Validators: []tfsdk.AttributeValidator{
+				ValidateIP(),
+			},
 		},
 		"mode": {
 			Type:        types.StringType,
@@ -628,6 +745,9 @@ var BondSchema tfsdk.Schema = tfsdk.Schema{
 			Type:        types.StringType,
 			Optional:    true,
 			Description: "A bond option. Please see the official kernel documentation.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateMAC(),
+			},
 		},
 		"arp_validate": {
 			Type:        types.StringType,
@@ -770,7 +890,9 @@ var VLANSchema tfsdk.Schema = tfsdk.Schema{
 			},
 			Description: "A list of IP addresses for the interface.",
 			Required:    true,
-			// TODO Add field validation
+			Validators: []tfsdk.AttributeValidator{
+				ValidateCIDR(),
+			},
 		},
 		"routes": {
 			Optional:    true,
@@ -805,20 +927,23 @@ var VIPSchema tfsdk.Schema = tfsdk.Schema{
 	Description: "Contains settings for configuring a Virtual Shared IP on an interface.",
 	Attributes: map[string]tfsdk.Attribute{
 		"ip": {
-			Type:     types.StringType,
-			Required: true,
-			// TODO validate
-			// ValidateFunc: validateIP,
+			Type:        types.StringType,
+			Required:    true,
 			Description: "Specifies the IP address to be used.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateIP(),
+			},
 		},
 		"equinix_metal_api_token": {
 			Type:        types.StringType,
 			Optional:    true,
+			Sensitive:   true,
 			Description: "Specifies the Equinix Metal API Token.",
 		},
 		"hetzner_cloud_api_token": {
 			Type:        types.StringType,
 			Optional:    true,
+			Sensitive:   true,
 			Description: "Specifies the Hetzner Cloud API Token.",
 		},
 	},
@@ -830,25 +955,28 @@ var RouteSchema tfsdk.Schema = tfsdk.Schema{
 	MarkdownDescription: "",
 	Attributes: map[string]tfsdk.Attribute{
 		"network": {
-			Type:     types.StringType,
-			Required: true,
-			// TODO Validate
-			// ValidateFunc: validateCIDR,
+			Type:        types.StringType,
+			Required:    true,
 			Description: "The route’s network (destination).",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateCIDR(),
+			},
 		},
 		"gateway": {
-			Type:     types.StringType,
-			Optional: true,
-			// TODO Validate
-			// ValidateFunc: validateIP,
+			Type:        types.StringType,
+			Optional:    true,
 			Description: "The route’s gateway (if empty, creates link scope route).",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateIP(),
+			},
 		},
 		"source": {
-			Type:     types.StringType,
-			Optional: true,
-			// TODO validate
-			// ValidateFunc: validateIP,
+			Type:        types.StringType,
+			Optional:    true,
 			Description: "The route’s source address.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateIP(),
+			},
 		},
 		"metric": {
 			Type:        types.Int64Type,
@@ -883,13 +1011,14 @@ var WireguardSchema tfsdk.Schema = tfsdk.Schema{
 			Description: "Automatically derived from the private_key field.",
 		},
 		"private_key": {
-			Type:      types.StringType,
-			Sensitive: true,
-			Optional:  true,
-			Computed:  true,
-			// TODO validate
-			// ValidateFunc: validateKey,
+			Type:        types.StringType,
+			Sensitive:   true,
+			Optional:    true,
+			Computed:    true,
 			Description: "Specifies a private key configuration (base64 encoded). If one is not provided it is automatically generated and populated this field",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateWireguardKey(),
+			},
 		},
 	},
 }
@@ -904,15 +1033,17 @@ var WireguardPeerSchema tfsdk.Schema = tfsdk.Schema{
 			},
 			Required:    true,
 			Description: "AllowedIPs specifies a list of allowed IP addresses in CIDR notation for this peer.",
-			// TODO add validator
-			// ValidateFunc: validateCIDR,
+			Validators: []tfsdk.AttributeValidator{
+				ValidateCIDR(),
+			},
 		},
 		"endpoint": {
-			Type:     types.StringType,
-			Required: true,
-			// TODO Add validator
-			//ValidateFunc: validateEndpoint64Type,
-			Description: "Specifies the endpoint of this peer entry.",
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "Specifies the endpoint of this peer entry. Peers without an endpoint only accept connections initiated by the peer.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateEndpoint(),
+			},
 		},
 		"persistent_keepalive_interval": {
 			Type:     types.Int64Type,
@@ -930,10 +1061,12 @@ var WireguardPeerSchema tfsdk.Schema = tfsdk.Schema{
 			Description: "Specifies the persistent keepalive interval for this peer. Provided in seconds.",
 		},
 		"public_key": {
-			Type:     types.StringType,
-			Required: true,
-			// TODO: Add validator for ValidateFunc: validateKey,
+			Type:        types.StringType,
+			Required:    true,
 			Description: "Specifies the public key of this peer.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateWireguardKey(),
+			},
 		},
 	},
 }
@@ -1016,9 +1149,9 @@ var EncryptionConfigSchema = tfsdk.Schema{
 var KeySchema = tfsdk.Schema{
 	MarkdownDescription: "Specifies system disk partition encryption settings.",
 	Attributes: map[string]tfsdk.Attribute{
-		// TODO have key_static and node_id mutually exclusive
 		"key_static": {
 			Optional:    true,
+			Sensitive:   true,
 			Description: "Represents a throw away key type.",
 			Type:        types.StringType,
 		},
@@ -1046,9 +1179,10 @@ var APIServerConfigSchema tfsdk.Schema = tfsdk.Schema{
 			PlanModifiers: tfsdk.AttributePlanModifiers{
 				tfsdk.UseStateForUnknown(),
 			},
-			Description: "The container image used in the API server manifest.",
-			// TODO validation
-			// ValidateFunc: validateImage,
+			Description: "The container image used in the API server manifest. Defaults to the image of the base configuration's `kubernetes_version`.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateImage(),
+			},
 		},
 		"extra_args": {
 			Type: types.MapType{
@@ -1074,11 +1208,12 @@ var APIServerConfigSchema tfsdk.Schema = tfsdk.Schema{
 			Type: types.ListType{
 				ElemType: types.StringType,
 			},
+			Optional: true,
 			Computed: true,
 			PlanModifiers: tfsdk.AttributePlanModifiers{
 				tfsdk.UseStateForUnknown(),
 			},
-			Description: "Extra certificate subject alternative names for the API server’s certificate.",
+			Description: "Extra certificate subject alternative names for the API server’s certificate. Defaults to the names derived from the base configuration.",
 		},
 		"disable_pod_security_policy": {
 			Type:     types.BoolType,
@@ -1098,6 +1233,274 @@ var APIServerConfigSchema tfsdk.Schema = tfsdk.Schema{
 			},
 			Attributes: tfsdk.ListNestedAttributes(AdmissionPluginSchema.Attributes),
 		},
+		"pod_security": {
+			Optional:    true,
+			Computed:    true,
+			Description: PodSecuritySchema.Description,
+			PlanModifiers: tfsdk.AttributePlanModifiers{
+				tfsdk.UseStateForUnknown(),
+			},
+			Attributes: tfsdk.SingleNestedAttributes(PodSecuritySchema.Attributes),
+		},
+		"audit_policy": {
+			Optional:    true,
+			Description: AuditPolicySchema.Description,
+			Attributes:  tfsdk.SingleNestedAttributes(AuditPolicySchema.Attributes),
+		},
+		"oidc": {
+			Optional:    true,
+			Description: OIDCSchema.Description,
+			Attributes:  tfsdk.SingleNestedAttributes(OIDCSchema.Attributes),
+		},
+	},
+}
+
+// PodSecuritySchema configures the PodSecurity admission plugin.
+var PodSecuritySchema tfsdk.Schema = tfsdk.Schema{
+	Description: "Configures the PodSecurity admission plugin, which enforces the Pod Security Standards. Defaults to enforcing " +
+		"baseline and auditing and warning about restricted when no admission plugins are configured. Conflicts with a " +
+		"PodSecurity entry in admission_control.",
+	Attributes: map[string]tfsdk.Attribute{
+		"enforce": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "The level pods violating it are rejected at, one of privileged, baseline or restricted.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidatePodSecurityLevel(),
+			},
+		},
+		"enforce_version": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "The version of the enforced level, latest or a Kubernetes minor version such as v1.24.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidatePodSecurityVersion(),
+			},
+		},
+		"audit": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "The level pods violating it are recorded in the audit log at, one of privileged, baseline or restricted.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidatePodSecurityLevel(),
+			},
+		},
+		"audit_version": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "The version of the audited level, latest or a Kubernetes minor version such as v1.24.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidatePodSecurityVersion(),
+			},
+		},
+		"warn": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "The level pods violating it trigger a warning at, one of privileged, baseline or restricted.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidatePodSecurityLevel(),
+			},
+		},
+		"warn_version": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "The version of the warned about level, latest or a Kubernetes minor version such as v1.24.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidatePodSecurityVersion(),
+			},
+		},
+		"exempt_namespaces": {
+			Type: types.ListType{
+				ElemType: types.StringType,
+			},
+			Optional:    true,
+			Description: "Namespaces whose pods aren't checked.",
+		},
+		"exempt_runtime_classes": {
+			Type: types.ListType{
+				ElemType: types.StringType,
+			},
+			Optional:    true,
+			Description: "Runtime class names whose pods aren't checked.",
+		},
+		"exempt_usernames": {
+			Type: types.ListType{
+				ElemType: types.StringType,
+			},
+			Optional:    true,
+			Description: "Usernames whose requests aren't checked.",
+		},
+	},
+}
+
+// AuditPolicySchema configures the policy of the apiserver's audit log.
+var AuditPolicySchema tfsdk.Schema = tfsdk.Schema{
+	Description: "The policy events are recorded in the apiserver's audit log by, either as a list of rules or as a raw " +
+		"policy document. Defaults to recording the metadata of all requests. Conflicts with the audit-policy-file extra argument.",
+	Attributes: map[string]tfsdk.Attribute{
+		"rules": {
+			Optional:    true,
+			Description: AuditRuleSchema.Description,
+			Attributes:  tfsdk.ListNestedAttributes(AuditRuleSchema.Attributes),
+		},
+		"omit_stages": {
+			Type: types.ListType{
+				ElemType: types.StringType,
+			},
+			Optional:    true,
+			Description: "The stages of requests no events are recorded at, any of RequestReceived, ResponseStarted, ResponseComplete or Panic.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateAuditStage(),
+			},
+		},
+		"policy": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "A raw audit.k8s.io/v1 Policy document. Conflicts with rules and omit_stages.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateYAML(),
+			},
+		},
+	},
+}
+
+// AuditRuleSchema describes a rule of the apiserver's audit policy.
+var AuditRuleSchema tfsdk.Schema = tfsdk.Schema{
+	Description: "The rules of the audit policy. A request is recorded at the level of the first rule it matches, " +
+		"a rule matches all requests by default.",
+	Attributes: map[string]tfsdk.Attribute{
+		"level": {
+			Type:        types.StringType,
+			Required:    true,
+			Description: "The level matching requests are recorded at, one of None, Metadata, Request or RequestResponse.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateAuditLevel(),
+			},
+		},
+		"users": {
+			Type: types.ListType{
+				ElemType: types.StringType,
+			},
+			Optional:    true,
+			Description: "The users matching requests are made by.",
+		},
+		"user_groups": {
+			Type: types.ListType{
+				ElemType: types.StringType,
+			},
+			Optional:    true,
+			Description: "The groups matching requests are made by a member of.",
+		},
+		"verbs": {
+			Type: types.ListType{
+				ElemType: types.StringType,
+			},
+			Optional:    true,
+			Description: "The verbs of matching requests, such as get or create.",
+		},
+		"resources": {
+			Optional:    true,
+			Description: AuditGroupResourcesSchema.Description,
+			Attributes:  tfsdk.ListNestedAttributes(AuditGroupResourcesSchema.Attributes),
+		},
+		"namespaces": {
+			Type: types.ListType{
+				ElemType: types.StringType,
+			},
+			Optional:    true,
+			Description: "The namespaces of the resources matching requests are made to.",
+		},
+		"non_resource_urls": {
+			Type: types.ListType{
+				ElemType: types.StringType,
+			},
+			Optional:    true,
+			Description: "The non-resource URLs of matching requests, such as /healthz*.",
+		},
+		"omit_stages": {
+			Type: types.ListType{
+				ElemType: types.StringType,
+			},
+			Optional:    true,
+			Description: "The stages of matching requests no events are recorded at, in addition to those of the policy.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateAuditStage(),
+			},
+		},
+	},
+}
+
+// AuditGroupResourcesSchema matches the resources of an API group in an audit rule.
+var AuditGroupResourcesSchema tfsdk.Schema = tfsdk.Schema{
+	Description: "The resources matching requests are made to.",
+	Attributes: map[string]tfsdk.Attribute{
+		"group": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "The API group of the resources. Defaults to the core group.",
+		},
+		"resources": {
+			Type: types.ListType{
+				ElemType: types.StringType,
+			},
+			Optional:    true,
+			Description: "The resources of the group, such as pods or pods/log. Defaults to all of the group's resources.",
+		},
+		"resource_names": {
+			Type: types.ListType{
+				ElemType: types.StringType,
+			},
+			Optional:    true,
+			Description: "The names of the resource instances. Defaults to all instances.",
+		},
+	},
+}
+
+// OIDCSchema configures the apiserver's OpenID Connect authentication.
+var OIDCSchema tfsdk.Schema = tfsdk.Schema{
+	Description: "Authenticates users by the ID tokens of an OpenID Connect provider. Conflicts with the oidc-* extra arguments.",
+	Attributes: map[string]tfsdk.Attribute{
+		"issuer_url": {
+			Type:        types.StringType,
+			Required:    true,
+			Description: "The URL of the provider, which must match the tokens' iss claim.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateURL("https"),
+			},
+		},
+		"client_id": {
+			Type:        types.StringType,
+			Required:    true,
+			Description: "The client ID tokens must be issued for.",
+		},
+		"username_claim": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "The claim the username is taken from. Defaults to sub.",
+		},
+		"username_prefix": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "The prefix added to usernames.",
+		},
+		"groups_claim": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "The claim the user's groups are taken from.",
+		},
+		"groups_prefix": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "The prefix added to groups.",
+		},
+		"ca": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "The PEM encoded CA certificate the provider's certificate is verified with. Defaults to the node's trusted CAs.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateCertificate(),
+			},
+		},
 	},
 }
 
@@ -1115,7 +1518,9 @@ var AdmissionPluginSchema tfsdk.Schema = tfsdk.Schema{
 			Type:        types.StringType,
 			Required:    true,
 			Description: "Configuration is an embedded configuration object to be used as the plugin’s configuration.",
-			// TODO Validate it is a properly formed YAML
+			Validators: []tfsdk.AttributeValidator{
+				ValidateYAML(),
+			},
 		},
 	},
 }
@@ -1131,9 +1536,10 @@ var ProxyConfigSchema tfsdk.Schema = tfsdk.Schema{
 			PlanModifiers: tfsdk.AttributePlanModifiers{
 				tfsdk.UseStateForUnknown(),
 			},
-			Description: "The container image used in the kube-proxy manifest.",
-			// TODO validate
-			// ValidateFunc: validateImage,
+			Description: "The container image used in the kube-proxy manifest. Defaults to the image of the base configuration's `kubernetes_version`.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateImage(),
+			},
 		},
 		"mode": {
 			Type:        types.StringType,
@@ -1179,6 +1585,9 @@ var TimeConfigSchema tfsdk.Schema = tfsdk.Schema{
 			Description: `Specifies the timeout when the node time is considered to be in sync unlocking the boot sequence.
 NTP sync will be still running in the background.
 Defaults to “infinity” (waiting forever for time sync)`,
+			Validators: []tfsdk.AttributeValidator{
+				ValidateDuration(),
+			},
 		},
 	},
 }
@@ -1205,6 +1614,9 @@ var LoggingDestinationSchema tfsdk.Schema = tfsdk.Schema{
 			Required:    true,
 			Description: "Where to send logs. Supported protocols are “tcp” and “udp”.",
 			Type:        types.StringType,
+			Validators: []tfsdk.AttributeValidator{
+				ValidateURL("tcp", "udp"),
+			},
 		},
 		"format": {
 			Required:    true,
@@ -1241,7 +1653,10 @@ var ControllerManagerConfigSchema tfsdk.Schema = tfsdk.Schema{
 			PlanModifiers: tfsdk.AttributePlanModifiers{
 				tfsdk.UseStateForUnknown(),
 			},
-			Description: "The container image used in the controller manager manifest.",
+			Description: "The container image used in the controller manager manifest. Defaults to the image of the base configuration's `kubernetes_version`.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateImage(),
+			},
 		},
 		"extra_args": {
 			Type: types.MapType{
@@ -1277,7 +1692,10 @@ var SchedulerConfigSchema tfsdk.Schema = tfsdk.Schema{
 			PlanModifiers: tfsdk.AttributePlanModifiers{
 				tfsdk.UseStateForUnknown(),
 			},
-			Description: "The container image used in the scheduler manifest.",
+			Description: "The container image used in the scheduler manifest. Defaults to the image of the base configuration's `kubernetes_version`.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateImage(),
+			},
 		},
 		"extra_args": {
 			Type: types.MapType{
@@ -1342,6 +1760,9 @@ var DiscoveryRegistriesConfigSchema tfsdk.Schema = tfsdk.Schema{
 			Type:                types.StringType,
 			Optional:            true,
 			MarkdownDescription: "External service endpoint.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateURL("http", "https"),
+			},
 		},
 	},
 }
@@ -1359,6 +1780,9 @@ var EtcdConfigSchema tfsdk.Schema = tfsdk.Schema{
 				tfsdk.UseStateForUnknown(),
 			},
 			Description: "The container image used to create the etcd service.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateImage(),
+			},
 		},
 		"ca_crt": {
 			Type:                types.StringType,
@@ -1369,8 +1793,8 @@ var EtcdConfigSchema tfsdk.Schema = tfsdk.Schema{
 		"ca_key": {
 			Type:                types.StringType,
 			Optional:            true,
-			Computed:            true,
-			MarkdownDescription: "PEM encoded etcd root certificate authority key.",
+			Sensitive:           true,
+			MarkdownDescription: "PEM encoded etcd root certificate authority key. Defaults to the key of the base configuration, which is kept out of state.",
 		},
 		"extra_args": {
 			Type: types.MapType{
@@ -1383,6 +1807,9 @@ var EtcdConfigSchema tfsdk.Schema = tfsdk.Schema{
 			Type:                types.StringType,
 			Optional:            true,
 			MarkdownDescription: "The subnet from which the advertise URL should be.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateCIDR(),
+			},
 		},
 	},
 }
@@ -1406,6 +1833,9 @@ Refer to [CoreDNS in the TalosOS Documentation](https://www.talos.dev/v1.0/refer
 				tfsdk.UseStateForUnknown(),
 			},
 			MarkdownDescription: "The `image` field is an override to the default coredns image.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateImage(),
+			},
 		},
 	},
 }
@@ -1420,6 +1850,9 @@ var AdminKubeconfigConfigSchema tfsdk.Schema = tfsdk.Schema{
 			Required: true,
 			MarkdownDescription: `Admin kubeconfig certificate lifetime (default is 1 year).
 Field format accepts any Go time.Duration format (‘1h’ for one hour, ‘10m’ for ten minutes).`,
+			Validators: []tfsdk.AttributeValidator{
+				ValidateDuration(),
+			},
 		},
 	},
 }
@@ -1434,7 +1867,9 @@ var ControlPlaneConfigSchema tfsdk.Schema = tfsdk.Schema{
 			Optional:    true,
 			Description: "Endpoint is the canonical controlplane endpoint, which can be an IP address or a DNS hostname.",
 			Computed:    true,
-			// TODO Verify well formed endpoint
+			Validators: []tfsdk.AttributeValidator{
+				ValidateURL("https"),
+			},
 		},
 		"local_api_server_port": {
 			Type:        types.Int64Type,
@@ -1451,8 +1886,14 @@ var InstallSchema tfsdk.Schema = tfsdk.Schema{
 	Description: "Represents installation options for Talos nodes.",
 	Attributes: map[string]tfsdk.Attribute{
 		"disk": {
-			Type:     types.StringType,
-			Optional: true,
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "The disk used for installations. Defaults to /dev/sda, conflicts with disk_selector.",
+		},
+		"disk_selector": {
+			Optional:    true,
+			Description: InstallDiskSelectorSchema.Description,
+			Attributes:  tfsdk.SingleNestedAttributes(InstallDiskSelectorSchema.Attributes),
 		},
 		"image": {
 			Type:     types.StringType,
@@ -1461,8 +1902,9 @@ var InstallSchema tfsdk.Schema = tfsdk.Schema{
 			PlanModifiers: tfsdk.AttributePlanModifiers{
 				tfsdk.UseStateForUnknown(),
 			},
-			// TODO validate
-			// ValidateFunc: validateImage,
+			Validators: []tfsdk.AttributeValidator{
+				ValidateImage(),
+			},
 		},
 		"bootloader": {
 			Type:     types.BoolType,
@@ -1491,6 +1933,66 @@ var InstallSchema tfsdk.Schema = tfsdk.Schema{
 	},
 }
 
+// InstallDiskSelectorSchema represents the disk query parameters for the install disk lookup.
+// Refer to https://www.talos.dev/v1.1/reference/configuration/#installdiskselector for more information.
+var InstallDiskSelectorSchema tfsdk.Schema = tfsdk.Schema{
+	Description: "Looks up the install disk by its properties instead of its path, so that the same configuration works on " +
+		"machines with different disk layouts. The first disk matching all set fields is used.",
+	Attributes: map[string]tfsdk.Attribute{
+		"size": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "Disk size condition, such as 4GB, > 1TB or <= 2TB.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateDiskSize(),
+			},
+		},
+		"name": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "Disk name, as found in /sys/block/<dev>/device/name.",
+		},
+		"model": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "Disk model, as found in /sys/block/<dev>/device/model. Supports wildcards, such as WDC*.",
+		},
+		"serial": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "Disk serial number, as found in /sys/block/<dev>/serial.",
+		},
+		"modalias": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "Disk modalias, as found in /sys/block/<dev>/device/modalias.",
+		},
+		"uuid": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "Disk UUID, as found in /sys/block/<dev>/uuid.",
+		},
+		"wwid": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "Disk WWID, as found in /sys/block/<dev>/wwid.",
+		},
+		"type": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "Disk type, one of ssd, hdd, nvme or sd.",
+			Validators: []tfsdk.AttributeValidator{
+				ValidateDiskType(),
+			},
+		},
+		"bus_path": {
+			Type:        types.StringType,
+			Optional:    true,
+			Description: "Disk bus path, such as /pci0000:00/0000:00:1f.2/ata1/host0/target0:0:0/0:0:0:0.",
+		},
+	},
+}
+
 // FileSchema describes a machine file and it's contents to be written onto the node's filesystem.
 // Refer to https://www.talos.dev/v1.0/reference/configuration/#installconfig for more information.
 var FileSchema tfsdk.Schema = tfsdk.Schema{
@@ -1576,6 +2078,7 @@ var CertBundleSchema = tfsdk.Schema{
 		"admin_key": {
 			Type:                types.StringType,
 			Optional:            true,
+			Sensitive:           true,
 			MarkdownDescription: "PEM encoded cluster admin key.",
 		},
 		"etcd_crt": {
@@ -1586,6 +2089,7 @@ var CertBundleSchema = tfsdk.Schema{
 		"etcd_key": {
 			Type:                types.StringType,
 			Required:            true,
+			Sensitive:           true,
 			MarkdownDescription: "PEM encoded etcd key.",
 		},
 		"k8s_crt": {
@@ -1596,6 +2100,7 @@ var CertBundleSchema = tfsdk.Schema{
 		"k8s_key": {
 			Type:                types.StringType,
 			Required:            true,
+			Sensitive:           true,
 			MarkdownDescription: "PEM encoded key for k8s.",
 		},
 		"k8s_aggregator_crt": {
@@ -1606,11 +2111,13 @@ var CertBundleSchema = tfsdk.Schema{
 		"k8s_aggregator_key": {
 			Type:                types.StringType,
 			Required:            true,
+			Sensitive:           true,
 			MarkdownDescription: "PEM encoded key for the k8s aggregator.",
 		},
 		"k8s_service_key": {
 			Type:                types.StringType,
 			Required:            true,
+			Sensitive:           true,
 			MarkdownDescription: "PEM encoded key for the k8s service.",
 		},
 		"os_crt": {
@@ -1621,6 +2128,7 @@ var CertBundleSchema = tfsdk.Schema{
 		"os_key": {
 			Type:                types.StringType,
 			Required:            true,
+			Sensitive:           true,
 			MarkdownDescription: "PEM encoded key for OS.",
 		},
 	},
@@ -1718,21 +2226,25 @@ var SecretBundleSchema = tfsdk.Schema{
 		"secret": {
 			Type:                types.StringType,
 			Optional:            true,
+			Sensitive:           true,
 			MarkdownDescription: "Unique cluster secret for Talos. Base64 encoded binary data.",
 		},
 		"bootstrap_token": {
 			Type:                types.StringType,
 			Optional:            true,
+			Sensitive:           true,
 			MarkdownDescription: "Unique token for Talos bootstrap.",
 		},
 		"aes_cbc_encryption": {
 			Type:                types.StringType,
 			Optional:            true,
+			Sensitive:           true,
 			MarkdownDescription: "Unique secret for Talos disk encryption. Base64 encoded binary data.",
 		},
 		"trustd_token": {
 			Type:                types.StringType,
 			Optional:            true,
+			Sensitive:           true,
 			MarkdownDescription: "Unique token for Talos trustd.",
 		},
 	},
@@ -0,0 +1,61 @@
+package datatypes
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// secretAttributes holds the names of the sensitive attributes of the node schemas.
+var secretAttributes = map[string]bool{
+	"password":                true,
+	"auth":                    true,
+	"identity_token":          true,
+	"client_identity_key":     true,
+	"private_key":             true,
+	"key_static":              true,
+	"equinix_metal_api_token": true,
+	"hetzner_cloud_api_token": true,
+	"ca_key":                  true,
+}
+
+// Secrets returns the values of the sensitive attributes held by a plan value, so that they can be masked wherever
+// they could leak, such as the provider's log output.
+func Secrets(value any) []string {
+	var secrets []string
+	collectSecrets(reflect.ValueOf(value), false, &secrets)
+
+	return secrets
+}
+
+func collectSecrets(value reflect.Value, secret bool, secrets *[]string) {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !value.IsNil() {
+			collectSecrets(value.Elem(), secret, secrets)
+		}
+	case reflect.Struct:
+		if value.Type() == stringType {
+			if s := value.Interface().(types.String); secret && !s.Null && !s.Unknown && s.Value != "" {
+				*secrets = append(*secrets, s.Value)
+			}
+			return
+		}
+		if isPrimitive(value.Type()) {
+			return
+		}
+		for i := 0; i < value.NumField(); i++ {
+			name, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("tfsdk"), ",")
+			collectSecrets(value.Field(i), secretAttributes[name], secrets)
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			collectSecrets(value.Index(i), secret, secrets)
+		}
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			collectSecrets(value.MapIndex(key), secret, secrets)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package datatypes
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSecrets(t *testing.T) {
+	config := TalosConfig{
+		Registry: &Registry{
+			Configs: map[string]RegistryConfig{
+				"registry.local": {Username: Wraps("user"), Password: Wraps("registry-password"), IdentityToken: Wraps("registry-token")},
+			},
+		},
+		Network: &NetworkConfig{
+			Hostname: Wraps("node"),
+			Devices: []NetworkDevice{
+				{
+					Name:      Wraps("eth0"),
+					VIP:       &VIP{IP: Wraps("10.0.0.100"), HetznerCloudAPIToken: Wraps("hcloud-token")},
+					Wireguard: &Wireguard{PrivateKey: Wraps("wireguard-key"), PublicKey: Wraps("wireguard-public")},
+				},
+			},
+		},
+		Encryption: &EncryptionData{
+			State: &EncryptionConfigData{Keys: []KeyConfig{{KeyStatic: Wraps("static-key"), Slot: Wrapi(0)}}},
+		},
+	}
+
+	secrets := Secrets(&config)
+	sort.Strings(secrets)
+
+	expected := []string{"hcloud-token", "registry-password", "registry-token", "static-key", "wireguard-key"}
+	if !reflect.DeepEqual(secrets, expected) {
+		t.Errorf("expected secrets %v, got %v", expected, secrets)
+	}
+}
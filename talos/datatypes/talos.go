@@ -264,9 +264,7 @@ MC4CAQAwBQYDK2VwBCIEIJCuZGq2GPWZnvtJvmwC+HIu6e95GecdBxC9qR4nGw4t
 			},
 		},
 		MachineSystemDiskEncryption: systemDiskEncryptionConfigExample,
-		MachineFeatures: &v1alpha1.FeaturesConfig{
-			RBAC: &testTrue,
-		},
+		MachineFeatures:             TalosFeaturesConfigExample,
 		MachineUdev: &v1alpha1.UdevConfig{
 			UdevRules: UdevExample,
 		},
@@ -589,6 +587,10 @@ MC4CAQAwBQYDK2VwBCIEIJCuZGq2GPWZnvtJvmwC+HIu6e95GecdBxC9qR4nGw4t
 		},
 	}
 
+	TalosFeaturesConfigExample = &v1alpha1.FeaturesConfig{
+		RBAC: &testTrue,
+	}
+
 	TalosNetworkKubeSpanExample = v1alpha1.NetworkKubeSpan{
 		KubeSpanEnabled:             true,
 		KubeSpanAllowDownPeerBypass: true,
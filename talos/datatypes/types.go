@@ -336,6 +336,10 @@ var (
 		AllowPeerDownBypass: Wrapb(true),
 	}
 
+	FeaturesConfigExample = &FeaturesConfig{
+		RBAC: Wrapb(true),
+	}
+
 	ControllerManagerExample = &ControllerManagerConfig{
 		Image:        s((&v1alpha1.ControllerManagerConfig{}).Image()),
 		ExtraArgs:    controllerManagerExtraArgsTFExample,
@@ -460,6 +464,7 @@ var (
 		Registry:            RegistryExample,
 		Udev:                TFUdevExample,
 		MachineControlPlane: MachineControlPlaneExample,
+		Features:            FeaturesConfigExample,
 
 		APIServer:             APIServerExample,
 		ControllerManager:     ControllerManagerExample,
@@ -498,6 +503,7 @@ type TalosConfig struct {
 	Encryption          *EncryptionData      `tfsdk:"encryption"`
 	Udev                MachineUdevRules     `tfsdk:"udev"`
 	MachineControlPlane *MachineControlPlane `tfsdk:"control_plane_config"`
+	Features            *FeaturesConfig      `tfsdk:"features"`
 
 	APIServer                *APIServerConfig         `tfsdk:"apiserver"`
 	ControllerManager        *ControllerManagerConfig `tfsdk:"controller_manager"`
@@ -515,13 +521,28 @@ type TalosConfig struct {
 }
 
 type InstallConfig struct {
-	Disk       types.String   `tfsdk:"disk"`
-	KernelArgs []types.String `tfsdk:"kernel_args"`
-	Image      types.String   `tfsdk:"image"`
-	Bootloader types.Bool     `tfsdk:"bootloader"`
-	LegacyBios types.Bool     `tfsdk:"legacy_bios"`
-	Extensions []types.String `tfsdk:"extensions"`
-	Wipe       types.Bool     `tfsdk:"wipe"`
+	Disk         types.String         `tfsdk:"disk"`
+	DiskSelector *InstallDiskSelector `tfsdk:"disk_selector"`
+	KernelArgs   []types.String       `tfsdk:"kernel_args"`
+	Image        types.String         `tfsdk:"image"`
+	Bootloader   types.Bool           `tfsdk:"bootloader"`
+	LegacyBios   types.Bool           `tfsdk:"legacy_bios"`
+	Extensions   []types.String       `tfsdk:"extensions"`
+	Wipe         types.Bool           `tfsdk:"wipe"`
+}
+
+// InstallDiskSelector represents the disk query parameters for the install disk lookup.
+// Refer to https://www.talos.dev/v1.1/reference/configuration/#installdiskselector for more information.
+type InstallDiskSelector struct {
+	Size     types.String `tfsdk:"size"`
+	Name     types.String `tfsdk:"name"`
+	Model    types.String `tfsdk:"model"`
+	Serial   types.String `tfsdk:"serial"`
+	Modalias types.String `tfsdk:"modalias"`
+	UUID     types.String `tfsdk:"uuid"`
+	WWID     types.String `tfsdk:"wwid"`
+	Type     types.String `tfsdk:"type"`
+	BusPath  types.String `tfsdk:"bus_path"`
 }
 
 // RegistryConfig specifies TLS & auth configuration for HTTPS image registries. The meaning of each
@@ -559,6 +580,16 @@ type KubeletConfig struct {
 	ExtraConfig        types.String            `tfsdk:"extra_config"`
 	RegisterWithFQDN   types.Bool              `tfsdk:"register_with_fqdn"`
 	NodeIPValidSubnets []types.String          `tfsdk:"node_ip_valid_subnets"`
+	Labels             map[string]types.String `tfsdk:"labels"`
+	Taints             []Taint                 `tfsdk:"taints"`
+}
+
+// Taint describes a taint the node is registered with.
+// Refer to https://kubernetes.io/docs/concepts/scheduling-eviction/taint-and-toleration/ for more information.
+type Taint struct {
+	Key    types.String `tfsdk:"key"`
+	Value  types.String `tfsdk:"value"`
+	Effect types.String `tfsdk:"effect"`
 }
 
 // ExtraMount wraps the OCI mount specification.
@@ -588,20 +619,29 @@ type NetworkConfig struct {
 
 // NetworkDevice describes a Talos Device configuration.
 // Refer to https://www.talos.dev/v1.0/reference/configuration/#device for more information.
-// TODO: Add network device selector field for interfaces and support it throughout the provider.
 type NetworkDevice struct {
-	Name        types.String   `tfsdk:"name"`
-	Addresses   []types.String `tfsdk:"addresses"`
-	Routes      []Route        `tfsdk:"routes"`
-	BondData    *BondData      `tfsdk:"bond"`
-	VLANs       []VLAN         `tfsdk:"vlans"`
-	MTU         types.Int64    `tfsdk:"mtu"`
-	DHCP        types.Bool     `tfsdk:"dhcp"`
-	DHCPOptions *DHCPOptions   `tfsdk:"dhcp_options"`
-	Ignore      types.Bool     `tfsdk:"ignore"`
-	Dummy       types.Bool     `tfsdk:"dummy"`
-	Wireguard   *Wireguard     `tfsdk:"wireguard"`
-	VIP         *VIP           `tfsdk:"vip"`
+	Name        types.String    `tfsdk:"name"`
+	Selector    *DeviceSelector `tfsdk:"device_selector"`
+	Addresses   []types.String  `tfsdk:"addresses"`
+	Routes      []Route         `tfsdk:"routes"`
+	BondData    *BondData       `tfsdk:"bond"`
+	VLANs       []VLAN          `tfsdk:"vlans"`
+	MTU         types.Int64     `tfsdk:"mtu"`
+	DHCP        types.Bool      `tfsdk:"dhcp"`
+	DHCPOptions *DHCPOptions    `tfsdk:"dhcp_options"`
+	Ignore      types.Bool      `tfsdk:"ignore"`
+	Dummy       types.Bool      `tfsdk:"dummy"`
+	Wireguard   *Wireguard      `tfsdk:"wireguard"`
+	VIP         *VIP            `tfsdk:"vip"`
+}
+
+// DeviceSelector selects a network device by its hardware rather than its interface name.
+// Refer to https://www.talos.dev/v1.1/reference/configuration/#networkdeviceselector for more information.
+type DeviceSelector struct {
+	BusPath      types.String `tfsdk:"bus_path"`
+	HardwareAddr types.String `tfsdk:"hardware_addr"`
+	PCIID        types.String `tfsdk:"pci_id"`
+	Driver       types.String `tfsdk:"driver"`
 }
 
 // BondData contains the various options for configuring a bonded interface.
@@ -811,6 +851,12 @@ type NetworkKubeSpan struct {
 	AllowPeerDownBypass types.Bool `tfsdk:"allow_peer_down_bypass"`
 }
 
+// FeaturesConfig describes the machine's Talos features.
+// Refer to https://www.talos.dev/v1.1/reference/configuration/#featuresconfig for more information.
+type FeaturesConfig struct {
+	RBAC types.Bool `tfsdk:"rbac"`
+}
+
 // APIServerConfig configures the Kubernetes control plane's apiserver.
 // Refer to https://www.talos.dev/v1.0/reference/configuration/#apiserverconfig for more information.
 type APIServerConfig struct {
@@ -821,6 +867,63 @@ type APIServerConfig struct {
 	CertSANS         []types.String          `tfsdk:"cert_sans"`
 	DisablePSP       types.Bool              `tfsdk:"disable_pod_security_policy"`
 	AdmissionPlugins []AdmissionPluginConfig `tfsdk:"admission_control"`
+	PodSecurity      *PodSecurityConfig      `tfsdk:"pod_security"`
+	AuditPolicy      *AuditPolicyConfig      `tfsdk:"audit_policy"`
+	OIDC             *OIDCConfig             `tfsdk:"oidc"`
+}
+
+// PodSecurityConfig configures the PodSecurity admission plugin, which enforces the Pod Security Standards.
+// Refer to https://kubernetes.io/docs/tasks/configure-pod-container/enforce-standards-admission-controller/ for more information.
+type PodSecurityConfig struct {
+	Enforce              types.String   `tfsdk:"enforce"`
+	EnforceVersion       types.String   `tfsdk:"enforce_version"`
+	Audit                types.String   `tfsdk:"audit"`
+	AuditVersion         types.String   `tfsdk:"audit_version"`
+	Warn                 types.String   `tfsdk:"warn"`
+	WarnVersion          types.String   `tfsdk:"warn_version"`
+	ExemptNamespaces     []types.String `tfsdk:"exempt_namespaces"`
+	ExemptRuntimeClasses []types.String `tfsdk:"exempt_runtime_classes"`
+	ExemptUsernames      []types.String `tfsdk:"exempt_usernames"`
+}
+
+// AuditPolicyConfig configures the policy events are recorded in the apiserver's audit log by, either as a list of
+// rules or as a raw policy document.
+// Refer to https://kubernetes.io/docs/tasks/debug/debug-cluster/audit/#audit-policy for more information.
+type AuditPolicyConfig struct {
+	Rules      []AuditRule    `tfsdk:"rules"`
+	OmitStages []types.String `tfsdk:"omit_stages"`
+	Policy     types.String   `tfsdk:"policy"`
+}
+
+// AuditRule maps the requests it matches to the level they are recorded at.
+type AuditRule struct {
+	Level           types.String          `tfsdk:"level"`
+	Users           []types.String        `tfsdk:"users"`
+	UserGroups      []types.String        `tfsdk:"user_groups"`
+	Verbs           []types.String        `tfsdk:"verbs"`
+	Resources       []AuditGroupResources `tfsdk:"resources"`
+	Namespaces      []types.String        `tfsdk:"namespaces"`
+	NonResourceURLs []types.String        `tfsdk:"non_resource_urls"`
+	OmitStages      []types.String        `tfsdk:"omit_stages"`
+}
+
+// AuditGroupResources matches the resources of an API group.
+type AuditGroupResources struct {
+	Group         types.String   `tfsdk:"group"`
+	Resources     []types.String `tfsdk:"resources"`
+	ResourceNames []types.String `tfsdk:"resource_names"`
+}
+
+// OIDCConfig configures the apiserver to authenticate users by the ID tokens of an OpenID Connect provider.
+// Refer to https://kubernetes.io/docs/reference/access-authn-authz/authentication/#openid-connect-tokens for more information.
+type OIDCConfig struct {
+	IssuerURL      types.String `tfsdk:"issuer_url"`
+	ClientID       types.String `tfsdk:"client_id"`
+	UsernameClaim  types.String `tfsdk:"username_claim"`
+	UsernamePrefix types.String `tfsdk:"username_prefix"`
+	GroupsClaim    types.String `tfsdk:"groups_claim"`
+	GroupsPrefix   types.String `tfsdk:"groups_prefix"`
+	CA             types.String `tfsdk:"ca"`
 }
 
 // AdmissionPluginConfig configures pod admssion rules on the kubelet64Type, denying execution to pods that don't fit them.
@@ -0,0 +1,461 @@
+package datatypes
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/talos-systems/go-blockdevice/blockdevice/util/disk"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	"gopkg.in/yaml.v3"
+)
+
+// This file contains the attribute validators used by the provider's schemas. Each validator checks a string value,
+// and is applied to every element when attached to a list or map of strings. Null and unknown values are skipped,
+// they are checked once they are known.
+
+var (
+	domainPattern  = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	versionPattern = regexp.MustCompile(`^(latest|v1\.[0-9]+)$`)
+	labelPattern   = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	imagePattern   = regexp.MustCompile(`^([a-zA-Z0-9]([-a-zA-Z0-9.]*[a-zA-Z0-9])?(:[0-9]+)?/)?[a-z0-9]+([._-][a-z0-9]+)*(/[a-z0-9]+([._-][a-z0-9]+)*)*(:[\w][\w.-]{0,127})?(@[a-z0-9]+:[a-f0-9]{32,})?$`)
+)
+
+// stringValidator validates string values with check.
+type stringValidator struct {
+	description string
+	check       func(string) error
+}
+
+// Description returns a plain text description of the validator's behavior.
+func (v stringValidator) Description(context.Context) string {
+	return v.description
+}
+
+// MarkdownDescription returns a markdown description of the validator's behavior.
+func (v stringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// Validate checks the attribute's value, or each of its elements if it is a list or map.
+func (v stringValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+	switch value := req.AttributeConfig.(type) {
+	case types.String:
+		if value.Null || value.Unknown {
+			return
+		}
+		if err := v.check(value.Value); err != nil {
+			resp.Diagnostics.AddAttributeError(req.AttributePath, "Invalid attribute value.", err.Error())
+		}
+	case types.List:
+		for i, elem := range value.Elems {
+			s, ok := elem.(types.String)
+			if !ok || s.Null || s.Unknown {
+				continue
+			}
+			if err := v.check(s.Value); err != nil {
+				resp.Diagnostics.AddAttributeError(req.AttributePath.AtListIndex(i), "Invalid attribute value.", err.Error())
+			}
+		}
+	case types.Map:
+		for key, elem := range value.Elems {
+			s, ok := elem.(types.String)
+			if !ok || s.Null || s.Unknown {
+				continue
+			}
+			if err := v.check(s.Value); err != nil {
+				resp.Diagnostics.AddAttributeError(req.AttributePath.AtMapKey(key), "Invalid attribute value.", err.Error())
+			}
+		}
+	}
+}
+
+// keyValidator validates the keys of map values with check.
+type keyValidator stringValidator
+
+// Description returns a plain text description of the validator's behavior.
+func (v keyValidator) Description(context.Context) string {
+	return v.description
+}
+
+// MarkdownDescription returns a markdown description of the validator's behavior.
+func (v keyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// Validate checks each of the keys of the attribute's map value.
+func (v keyValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+	value, ok := req.AttributeConfig.(types.Map)
+	if !ok {
+		return
+	}
+
+	for key := range value.Elems {
+		if err := v.check(key); err != nil {
+			resp.Diagnostics.AddAttributeError(req.AttributePath.AtMapKey(key), "Invalid attribute key.", err.Error())
+		}
+	}
+}
+
+// ValidateMAC checks that values are MAC addresses.
+func ValidateMAC() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be a MAC address",
+		check: func(v string) error {
+			if _, err := net.ParseMAC(v); err != nil {
+				return fmt.Errorf("must be a MAC address, got %q: %w", v, err)
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateCIDR checks that values are IP addresses in CIDR notation.
+func ValidateCIDR() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be an IP address in CIDR notation",
+		check: func(v string) error {
+			if _, _, err := net.ParseCIDR(v); err != nil {
+				return fmt.Errorf("must be an IP address in CIDR notation, got %q", v)
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateSubnet checks that values are IP addresses in CIDR notation, optionally negated with a leading "!" as
+// used by Talos subnet filters.
+func ValidateSubnet() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: `value must be an IP address in CIDR notation, optionally prefixed with "!"`,
+		check: func(v string) error {
+			if _, _, err := net.ParseCIDR(strings.TrimPrefix(v, "!")); err != nil {
+				return fmt.Errorf(`must be an IP address in CIDR notation, optionally prefixed with "!", got %q`, v)
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateIP checks that values are IP addresses.
+func ValidateIP() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be an IP address",
+		check: func(v string) error {
+			if net.ParseIP(v) == nil {
+				return fmt.Errorf("must be an IP address, got %q", v)
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateImage checks that values are container image references.
+func ValidateImage() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be a container image reference",
+		check: func(v string) error {
+			if !imagePattern.MatchString(v) {
+				return fmt.Errorf("must be a container image reference such as ghcr.io/siderolabs/installer:v1.1.1, got %q", v)
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateWireguardKey checks that values are base64 encoded Wireguard keys.
+func ValidateWireguardKey() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be a base64 encoded Wireguard key",
+		check: func(v string) error {
+			if _, err := wgtypes.ParseKey(v); err != nil {
+				return fmt.Errorf("must be a base64 encoded Wireguard key: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateDomain checks that values are lowercase RFC 1123 subdomains, as required of host and node names.
+func ValidateDomain() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be a lowercase RFC 1123 subdomain",
+		check: func(v string) error {
+			if len(v) > 253 || !domainPattern.MatchString(v) {
+				return fmt.Errorf("must be a lowercase RFC 1123 subdomain, got %q", v)
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateEndpoint checks that values are an IP address or hostname followed by a port.
+func ValidateEndpoint() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be an IP address or hostname followed by a port",
+		check: func(v string) error {
+			host, port, err := net.SplitHostPort(v)
+			if err != nil || port == "" {
+				return fmt.Errorf("must be an IP address or hostname followed by a port, got %q", v)
+			}
+			if net.ParseIP(host) == nil && !domainPattern.MatchString(host) {
+				return fmt.Errorf("must be an IP address or hostname followed by a port, got %q", v)
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateURL checks that values are absolute URLs using one of schemes.
+func ValidateURL(schemes ...string) tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be a URL with one of the schemes " + strings.Join(schemes, ", "),
+		check: func(v string) error {
+			u, err := url.Parse(v)
+			if err != nil {
+				return fmt.Errorf("must be a URL, got %q: %w", v, err)
+			}
+			if u.Host == "" {
+				return fmt.Errorf("must be an absolute URL with a host, got %q", v)
+			}
+			for _, scheme := range schemes {
+				if u.Scheme == scheme {
+					return nil
+				}
+			}
+			return fmt.Errorf("must use one of the URL schemes %s, got %q", strings.Join(schemes, ", "), v)
+		},
+	}
+}
+
+// ValidateDuration checks that values are Go durations, such as 1h or 10m.
+func ValidateDuration() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be a duration such as 1h or 10m",
+		check: func(v string) error {
+			if _, err := time.ParseDuration(v); err != nil {
+				return fmt.Errorf("must be a duration such as 1h or 10m, got %q", v)
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateOneOf checks that values are one of allowed.
+func ValidateOneOf(allowed ...string) tfsdk.AttributeValidator {
+	alternatives := strings.Join(allowed[:len(allowed)-1], ", ") + " or " + allowed[len(allowed)-1]
+	if len(allowed) == 1 {
+		alternatives = allowed[0]
+	}
+
+	return stringValidator{
+		description: "value must be one of " + alternatives,
+		check: func(v string) error {
+			for _, a := range allowed {
+				if v == a {
+					return nil
+				}
+			}
+			return fmt.Errorf("must be one of %s, got %q", alternatives, v)
+		},
+	}
+}
+
+// ValidateDiskSize checks that values are install disk size conditions.
+func ValidateDiskSize() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be a disk size condition such as 4GB, > 1TB or <= 2TB",
+		check: func(v string) error {
+			if _, err := diskSizeMatcher(v); err != nil {
+				return fmt.Errorf("must be a disk size condition such as 4GB, > 1TB or <= 2TB, got %q: %w", v, err)
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateDiskType checks that values are disk types.
+func ValidateDiskType() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be one of ssd, hdd, nvme or sd",
+		check: func(v string) error {
+			// Types are read back in lower case.
+			if _, err := disk.ParseType(v); err != nil || strings.ToLower(v) != v {
+				return fmt.Errorf("must be one of ssd, hdd, nvme or sd, got %q", v)
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateLabelKey checks that values are Kubernetes label keys, a name optionally prefixed with a DNS subdomain.
+func ValidateLabelKey() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be a Kubernetes label key",
+		check:       checkLabelKey,
+	}
+}
+
+// ValidateLabelKeys checks that the keys of map values are Kubernetes label keys.
+func ValidateLabelKeys() tfsdk.AttributeValidator {
+	return keyValidator{
+		description: "keys must be Kubernetes label keys",
+		check:       checkLabelKey,
+	}
+}
+
+func checkLabelKey(v string) error {
+	name := v
+	if prefix, suffix, ok := strings.Cut(v, "/"); ok {
+		if len(prefix) > 253 || !domainPattern.MatchString(prefix) {
+			return fmt.Errorf("must be a label key whose prefix is a DNS subdomain, got %q", v)
+		}
+		name = suffix
+	}
+
+	if len(name) > 63 || !labelPattern.MatchString(name) {
+		return fmt.Errorf("must be a label key whose name is at most 63 alphanumeric characters, '-', '_' or '.', got %q", v)
+	}
+	return nil
+}
+
+// ValidateLabelValue checks that values are Kubernetes label values.
+func ValidateLabelValue() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be a Kubernetes label value",
+		check: func(v string) error {
+			if v != "" && (len(v) > 63 || !labelPattern.MatchString(v)) {
+				return fmt.Errorf("must be a label value of at most 63 alphanumeric characters, '-', '_' or '.', got %q", v)
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateTaintEffect checks that values are Kubernetes taint effects.
+func ValidateTaintEffect() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be one of NoSchedule, PreferNoSchedule or NoExecute",
+		check: func(v string) error {
+			switch v {
+			case "NoSchedule", "PreferNoSchedule", "NoExecute":
+				return nil
+			}
+			return fmt.Errorf("must be one of NoSchedule, PreferNoSchedule or NoExecute, got %q", v)
+		},
+	}
+}
+
+// ValidatePodSecurityLevel checks that values are Pod Security Standards levels.
+func ValidatePodSecurityLevel() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be one of privileged, baseline or restricted",
+		check: func(v string) error {
+			switch v {
+			case "privileged", "baseline", "restricted":
+				return nil
+			}
+			return fmt.Errorf("must be one of privileged, baseline or restricted, got %q", v)
+		},
+	}
+}
+
+// ValidatePodSecurityVersion checks that values are Pod Security Standards versions.
+func ValidatePodSecurityVersion() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be latest or a Kubernetes minor version such as v1.24",
+		check: func(v string) error {
+			if !versionPattern.MatchString(v) {
+				return fmt.Errorf("must be latest or a Kubernetes minor version such as v1.24, got %q", v)
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateAuditLevel checks that values are audit levels.
+func ValidateAuditLevel() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be one of None, Metadata, Request or RequestResponse",
+		check: func(v string) error {
+			switch v {
+			case "None", "Metadata", "Request", "RequestResponse":
+				return nil
+			}
+			return fmt.Errorf("must be one of None, Metadata, Request or RequestResponse, got %q", v)
+		},
+	}
+}
+
+// ValidateAuditStage checks that values are the stages of a request audit events are recorded at.
+func ValidateAuditStage() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be one of RequestReceived, ResponseStarted, ResponseComplete or Panic",
+		check: func(v string) error {
+			switch v {
+			case "RequestReceived", "ResponseStarted", "ResponseComplete", "Panic":
+				return nil
+			}
+			return fmt.Errorf("must be one of RequestReceived, ResponseStarted, ResponseComplete or Panic, got %q", v)
+		},
+	}
+}
+
+// ValidateCertificate checks that values are PEM encoded certificates.
+func ValidateCertificate() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be a PEM encoded certificate",
+		check: func(v string) error {
+			block, _ := pem.Decode([]byte(v))
+			if block == nil || block.Type != "CERTIFICATE" {
+				return fmt.Errorf("must be a PEM encoded certificate")
+			}
+			if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+				return fmt.Errorf("must be a PEM encoded certificate: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateYAML checks that values are YAML documents.
+func ValidateYAML() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be a YAML document",
+		check: func(v string) error {
+			var doc any
+			if err := yaml.Unmarshal([]byte(v), &doc); err != nil {
+				return fmt.Errorf("must be a YAML document: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+// ValidateBaseConfig checks that values are the base_config of a talos_configuration resource, the JSON encoded
+// input Talos configurations are generated from.
+func ValidateBaseConfig() tfsdk.AttributeValidator {
+	return stringValidator{
+		description: "value must be the base_config of a talos_configuration resource",
+		check: func(v string) error {
+			input := generate.Input{}
+			if err := json.Unmarshal([]byte(v), &input); err != nil {
+				return fmt.Errorf("must be the base_config of a talos_configuration resource, unable to decode it: %w", err)
+			}
+			if input.Certs == nil {
+				return fmt.Errorf("must be the base_config of a talos_configuration resource, it holds no certificates")
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,324 @@
+package datatypes
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// This file contains the checks across attributes which Talos otherwise only rejects once a node is configured.
+// Values which aren't known yet are skipped, the configuration is checked again once they are.
+
+const bondMode8023AD = "802.3ad"
+
+// ValidateDevices checks the relationships between a node's network devices. paths holds the attribute path of each
+// device, errors are reported at the attribute causing them.
+func ValidateDevices(devices []NetworkDevice, paths []path.Path) (diags diag.Diagnostics) {
+	// Bond members, by interface name, and the device of the bond they belong to.
+	members := map[string]int{}
+
+	for i, device := range devices {
+		if device.BondData == nil {
+			continue
+		}
+
+		for j, member := range device.BondData.Interfaces {
+			if !known(member) {
+				continue
+			}
+
+			if other, ok := members[member.Value]; ok && other != i {
+				diags.AddAttributeError(paths[i].AtName("bond").AtName("interfaces").AtListIndex(j), "Interface bonded twice.",
+					fmt.Sprintf("Interface %s is already a member of the bond of device %s.", member.Value, devices[other].Name.Value))
+				continue
+			}
+			members[member.Value] = i
+		}
+
+		diags.Append(validateBond(device.BondData, paths[i].AtName("bond"))...)
+	}
+
+	for i, device := range devices {
+		diags.Append(validateDeviceSelector(device, paths[i])...)
+
+		if bond, ok := members[device.Name.Value]; ok && known(device.Name) {
+			diags.Append(validateBondMember(device, devices[bond].Name.Value, paths[i])...)
+		}
+
+		diags.Append(validateVIP(device.VIP, device.Addresses, paths[i])...)
+
+		vlans := map[int64]int{}
+		for j, vlan := range device.VLANs {
+			diags.Append(validateVIP(vlan.VIP, vlan.Addresses, paths[i].AtName("vlans").AtListIndex(j))...)
+
+			if vlan.VLANId.Null || vlan.VLANId.Unknown {
+				continue
+			}
+			if other, ok := vlans[vlan.VLANId.Value]; ok {
+				diags.AddAttributeError(paths[i].AtName("vlans").AtListIndex(j).AtName("vlan_id"), "Duplicate VLAN ID.",
+					fmt.Sprintf("VLAN %d is already configured by VLAN %d of the device.", vlan.VLANId.Value, other))
+				continue
+			}
+			vlans[vlan.VLANId.Value] = j
+		}
+	}
+
+	return
+}
+
+// validateDeviceSelector checks that a device is chosen either by its interface name or by a selector matching on at
+// least one field.
+func validateDeviceSelector(device NetworkDevice, p path.Path) (diags diag.Diagnostics) {
+	if device.Selector == nil {
+		if device.Name.Null {
+			diags.AddAttributeError(p, "Network device without an interface.", "Either name or device_selector must be set.")
+		}
+		return
+	}
+
+	if !device.Name.Null {
+		diags.AddAttributeError(p.AtName("device_selector"), "Conflicting network device interface.",
+			"Only one of name or device_selector can be set.")
+	}
+
+	selector := device.Selector
+	for _, field := range []types.String{selector.BusPath, selector.HardwareAddr, selector.PCIID, selector.Driver} {
+		if field.Unknown || set(field) {
+			return
+		}
+	}
+	diags.AddAttributeError(p.AtName("device_selector"), "Empty device selector.",
+		"At least one of bus_path, hardware_addr, pci_id or driver must be set.")
+
+	return
+}
+
+// validateBond checks that options which only apply to LACP (802.3ad) bonds aren't set for other modes.
+func validateBond(bond *BondData, p path.Path) (diags diag.Diagnostics) {
+	if !known(bond.Mode) || bond.Mode.Value == bondMode8023AD {
+		return
+	}
+
+	lacp := []struct {
+		name string
+		set  bool
+	}{
+		{"lacp_rate", set(bond.LacpRate)},
+		{"ad_actor_system", set(bond.AdActorSystem)},
+		{"ad_select", set(bond.AdSelect)},
+		{"ad_actor_sys_prio", !bond.AdActorSysPrio.Null && !bond.AdActorSysPrio.Unknown && bond.AdActorSysPrio.Value > 0},
+		{"ad_user_port_key", !bond.AdUserPortKey.Null && !bond.AdUserPortKey.Unknown && bond.AdUserPortKey.Value > 0},
+	}
+
+	for _, option := range lacp {
+		if option.set {
+			diags.AddAttributeError(p.AtName(option.name), "Option only available in 802.3ad mode.",
+				fmt.Sprintf("%s only applies to LACP bonds, the bond's mode is %s.", option.name, bond.Mode.Value))
+		}
+	}
+
+	return
+}
+
+// validateBondMember checks that an interface bonded into bond isn't addressed on its own. Talos doesn't support
+// DHCP, static addresses or a VIP on bond members, those belong on the bond's device.
+func validateBondMember(device NetworkDevice, bond string, p path.Path) (diags diag.Diagnostics) {
+	detail := fmt.Sprintf("Interface %s is a member of the bond of device %s and can't be addressed on its own, configure it on the bond instead.",
+		device.Name.Value, bond)
+
+	if !device.DHCP.Null && !device.DHCP.Unknown && device.DHCP.Value {
+		diags.AddAttributeError(p.AtName("dhcp"), "Bond member configured as a standalone device.", detail)
+	}
+	if len(device.Addresses) > 0 {
+		diags.AddAttributeError(p.AtName("addresses"), "Bond member configured as a standalone device.", detail)
+	}
+	if device.VIP != nil {
+		diags.AddAttributeError(p.AtName("vip"), "Bond member configured as a standalone device.", detail)
+	}
+	if len(device.VLANs) > 0 {
+		diags.AddAttributeError(p.AtName("vlans"), "Bond member configured as a standalone device.", detail)
+	}
+
+	return
+}
+
+// validateVIP checks that a VIP lies within one of the subnets of the static addresses it is shared on. Interfaces
+// without static addresses are configured through DHCP, their subnet isn't known up front.
+func validateVIP(vip *VIP, addresses []types.String, p path.Path) (diags diag.Diagnostics) {
+	if vip == nil || !known(vip.IP) || len(addresses) == 0 {
+		return
+	}
+
+	ip, err := netip.ParseAddr(vip.IP.Value)
+	if err != nil {
+		return
+	}
+
+	for _, address := range addresses {
+		if !known(address) {
+			return
+		}
+
+		prefix, err := netip.ParsePrefix(address.Value)
+		if err != nil {
+			return
+		}
+		if prefix.Masked().Contains(ip) {
+			return
+		}
+	}
+
+	diags.AddAttributeError(p.AtName("vip").AtName("ip"), "VIP outside of the interface's subnets.",
+		fmt.Sprintf("%s isn't within the subnet of any of the interface's addresses, it can't be shared on the interface.", vip.IP.Value))
+
+	return
+}
+
+// ValidateEncryption checks the keys of a node's partition encryption, p is the path of the encryption attribute.
+func ValidateEncryption(encryption *EncryptionData, p path.Path) (diags diag.Diagnostics) {
+	if encryption == nil {
+		return
+	}
+
+	diags.Append(validateKeys(encryption.State, p.AtName("state"))...)
+	diags.Append(validateKeys(encryption.Ephemeral, p.AtName("ephemeral"))...)
+
+	return
+}
+
+// validateKeys checks that each key of a partition uses a single key source and its own slot.
+func validateKeys(config *EncryptionConfigData, p path.Path) (diags diag.Diagnostics) {
+	if config == nil {
+		return
+	}
+
+	slots := map[int64]int{}
+	for i, key := range config.Keys {
+		keyPath := p.AtName("keys").AtListIndex(i)
+		static := set(key.KeyStatic)
+		nodeID := !key.NodeID.Null && !key.NodeID.Unknown && key.NodeID.Value
+
+		if static && nodeID {
+			diags.AddAttributeError(keyPath.AtName("node_id"), "Conflicting encryption key sources.",
+				"A key is either derived from the node's ID or static, set only one of key_static and node_id.")
+		}
+		if !static && !nodeID && !key.KeyStatic.Unknown && !key.NodeID.Unknown {
+			diags.AddAttributeError(keyPath, "Encryption key without a source.", "Set one of key_static and node_id.")
+		}
+
+		if key.Slot.Unknown {
+			continue
+		}
+		if other, ok := slots[key.Slot.Value]; ok {
+			diags.AddAttributeError(keyPath.AtName("slot"), "Duplicate encryption key slot.",
+				fmt.Sprintf("Slot %d is already used by key %d of the partition.", key.Slot.Value, other))
+			continue
+		}
+		slots[key.Slot.Value] = i
+	}
+
+	return
+}
+
+// ValidateInstallDisk checks that the install disk is either set by its path or looked up by a selector matching on
+// at least one field.
+func ValidateInstallDisk(disk types.String, selector *InstallDiskSelector, selectorPath path.Path) (diags diag.Diagnostics) {
+	if selector == nil {
+		return
+	}
+
+	if !disk.Null {
+		diags.AddAttributeError(selectorPath, "Conflicting install disk.", "The install disk is either set by its path or looked up by a selector, not both.")
+	}
+
+	for _, field := range []types.String{selector.Size, selector.Name, selector.Model, selector.Serial, selector.Modalias,
+		selector.UUID, selector.WWID, selector.Type, selector.BusPath} {
+		if field.Unknown || set(field) {
+			return
+		}
+	}
+	diags.AddAttributeError(selectorPath, "Empty install disk selector.", "At least one of the selector's fields must be set.")
+
+	return
+}
+
+// ValidateKubelet checks that the node's labels and taints aren't also set through the kubelet's extra arguments.
+func ValidateKubelet(kubelet *KubeletConfig, p path.Path) (diags diag.Diagnostics) {
+	if kubelet == nil {
+		return
+	}
+
+	conflicts := []struct {
+		arg, attribute string
+		set            bool
+	}{
+		{nodeLabelsArg, "labels", len(kubelet.Labels) > 0},
+		{nodeTaintsArg, "taints", len(kubelet.Taints) > 0},
+	}
+
+	for _, conflict := range conflicts {
+		if _, ok := kubelet.ExtraArgs[conflict.arg]; ok && conflict.set {
+			diags.AddAttributeError(p.AtName("extra_args").AtMapKey(conflict.arg), "Conflicting kubelet argument.",
+				fmt.Sprintf("The %s argument is rendered from %s, set only one of them.", conflict.arg, conflict.attribute))
+		}
+	}
+
+	return
+}
+
+// ValidateAPIServer checks that the PodSecurity admission plugin is configured only once, that the audit policy is
+// set by either its rules or a raw document, and that the audit policy and OIDC settings aren't also set through the
+// apiserver's extra arguments.
+func ValidateAPIServer(apiServer *APIServerConfig, p path.Path) (diags diag.Diagnostics) {
+	if apiServer == nil {
+		return
+	}
+
+	if apiServer.PodSecurity != nil {
+		for i, plugin := range apiServer.AdmissionPlugins {
+			if plugin.Name.Value == podSecurityPlugin {
+				diags.AddAttributeError(p.AtName("admission_control").AtListIndex(i), "Conflicting PodSecurity configuration.",
+					"The PodSecurity admission plugin is rendered from pod_security, set only one of them.")
+			}
+		}
+	}
+
+	if policy := apiServer.AuditPolicy; policy != nil {
+		if !policy.Policy.Null && (policy.Rules != nil || policy.OmitStages != nil) {
+			diags.AddAttributeError(p.AtName("audit_policy").AtName("policy"), "Conflicting audit policy.",
+				"The audit policy is either set by its rules or as a raw document, not both.")
+		}
+		if policy.Policy.Null && policy.Rules == nil {
+			diags.AddAttributeError(p.AtName("audit_policy"), "Empty audit policy.", "Either rules or policy must be set.")
+		}
+		if _, ok := apiServer.ExtraArgs[auditPolicyArg]; ok {
+			diags.AddAttributeError(p.AtName("extra_args").AtMapKey(auditPolicyArg), "Conflicting apiserver argument.",
+				fmt.Sprintf("The %s argument is rendered from audit_policy, set only one of them.", auditPolicyArg))
+		}
+	}
+
+	if apiServer.OIDC != nil {
+		for arg := range apiServer.ExtraArgs {
+			if strings.HasPrefix(arg, "oidc-") {
+				diags.AddAttributeError(p.AtName("extra_args").AtMapKey(arg), "Conflicting apiserver argument.",
+					fmt.Sprintf("The %s argument is rendered from oidc, set only one of them.", arg))
+			}
+		}
+	}
+
+	return
+}
+
+// known reports whether a string holds a known value.
+func known(value types.String) bool {
+	return !value.Null && !value.Unknown
+}
+
+// set reports whether a string holds a known, non-empty value.
+func set(value types.String) bool {
+	return known(value) && value.Value != ""
+}
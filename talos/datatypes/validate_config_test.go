@@ -0,0 +1,202 @@
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValidateDevices(t *testing.T) {
+	root := path.Root("devices")
+
+	tests := []struct {
+		name    string
+		devices []NetworkDevice
+		errors  []path.Path
+	}{
+		{
+			name: "valid",
+			devices: []NetworkDevice{
+				{
+					Name:      Wraps("bond0"),
+					Addresses: Wrapsl("192.168.1.10/24"),
+					BondData:  &BondData{Interfaces: Wrapsl("eth0", "eth1"), Mode: Wraps("802.3ad"), LacpRate: Wraps("fast")},
+					VIP:       &VIP{IP: Wraps("192.168.1.100")},
+					VLANs: []VLAN{
+						{VLANId: Wrapi(10), Addresses: Wrapsl("10.0.10.2/24")},
+						{VLANId: Wrapi(20), DHCP: Wrapb(true)},
+					},
+				},
+				{Name: Wraps("eth0"), MTU: Wrapi(9000)},
+			},
+		},
+		{
+			name: "bond member addressed",
+			devices: []NetworkDevice{
+				{Name: Wraps("bond0"), BondData: &BondData{Interfaces: Wrapsl("eth0"), Mode: Wraps("active-backup")}},
+				{Name: Wraps("eth0"), DHCP: Wrapb(true), Addresses: Wrapsl("192.168.1.10/24")},
+			},
+			errors: []path.Path{root.AtListIndex(1).AtName("dhcp"), root.AtListIndex(1).AtName("addresses")},
+		},
+		{
+			name: "interface in two bonds",
+			devices: []NetworkDevice{
+				{Name: Wraps("bond0"), BondData: &BondData{Interfaces: Wrapsl("eth0"), Mode: Wraps("active-backup")}},
+				{Name: Wraps("bond1"), BondData: &BondData{Interfaces: Wrapsl("eth1", "eth0"), Mode: Wraps("active-backup")}},
+			},
+			errors: []path.Path{root.AtListIndex(1).AtName("bond").AtName("interfaces").AtListIndex(1)},
+		},
+		{
+			name: "lacp option in active-backup mode",
+			devices: []NetworkDevice{
+				{Name: Wraps("bond0"), BondData: &BondData{Interfaces: Wrapsl("eth0"), Mode: Wraps("active-backup"), LacpRate: Wraps("fast"), AdUserPortKey: Wrapi(1)}},
+			},
+			errors: []path.Path{root.AtListIndex(0).AtName("bond").AtName("lacp_rate"), root.AtListIndex(0).AtName("bond").AtName("ad_user_port_key")},
+		},
+		{
+			name: "vip outside of subnets",
+			devices: []NetworkDevice{
+				{Name: Wraps("eth0"), Addresses: Wrapsl("192.168.1.10/24"), VIP: &VIP{IP: Wraps("192.168.2.100")}},
+				{Name: Wraps("eth1"), DHCP: Wrapb(true), VIP: &VIP{IP: Wraps("10.0.0.100")}},
+			},
+			errors: []path.Path{root.AtListIndex(0).AtName("vip").AtName("ip")},
+		},
+		{
+			name: "duplicate vlan id",
+			devices: []NetworkDevice{
+				{Name: Wraps("eth0"), VLANs: []VLAN{{VLANId: Wrapi(10)}, {VLANId: Wrapi(20)}, {VLANId: Wrapi(10)}}},
+			},
+			errors: []path.Path{root.AtListIndex(0).AtName("vlans").AtListIndex(2).AtName("vlan_id")},
+		},
+		{
+			name: "device selectors",
+			devices: []NetworkDevice{
+				{Name: types.String{Null: true}, Selector: &DeviceSelector{HardwareAddr: Wraps("*:f0:ab"), Driver: types.String{Null: true}}},
+				{Name: types.String{Null: true}, Selector: &DeviceSelector{Driver: types.String{Unknown: true}}},
+				{Name: Wraps("eth0"), Selector: &DeviceSelector{Driver: Wraps("virtio_net")}},
+				{Name: types.String{Null: true}, Selector: &DeviceSelector{BusPath: types.String{Null: true}}},
+				{Name: types.String{Null: true}, MTU: Wrapi(9000)},
+			},
+			errors: []path.Path{root.AtListIndex(2).AtName("device_selector"), root.AtListIndex(3).AtName("device_selector"), root.AtListIndex(4)},
+		},
+		{
+			name: "unknown values",
+			devices: []NetworkDevice{
+				{Name: Wraps("bond0"), BondData: &BondData{Interfaces: []types.String{{Unknown: true}}, Mode: types.String{Unknown: true}, LacpRate: Wraps("fast")}},
+				{Name: Wraps("eth0"), Addresses: []types.String{{Unknown: true}}, VIP: &VIP{IP: Wraps("10.0.0.100")}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		paths := make([]path.Path, len(tc.devices))
+		for i := range tc.devices {
+			paths[i] = root.AtListIndex(i)
+		}
+
+		checkErrorPaths(t, tc.name, ValidateDevices(tc.devices, paths), tc.errors)
+	}
+}
+
+func TestValidateEncryption(t *testing.T) {
+	root := path.Root("encryption")
+
+	encryption := &EncryptionData{
+		State: &EncryptionConfigData{
+			Keys: []KeyConfig{
+				{NodeID: Wrapb(true), Slot: Wrapi(0)},
+				{KeyStatic: Wraps("secret"), NodeID: Wrapb(true), Slot: Wrapi(1)},
+				{KeyStatic: Wraps("secret"), Slot: Wrapi(0)},
+			},
+		},
+		Ephemeral: &EncryptionConfigData{
+			Keys: []KeyConfig{{Slot: Wrapi(0), NodeID: types.Bool{Null: true}, KeyStatic: types.String{Null: true}}},
+		},
+	}
+
+	checkErrorPaths(t, "encryption", ValidateEncryption(encryption, root), []path.Path{
+		root.AtName("state").AtName("keys").AtListIndex(1).AtName("node_id"),
+		root.AtName("state").AtName("keys").AtListIndex(2).AtName("slot"),
+		root.AtName("ephemeral").AtName("keys").AtListIndex(0),
+	})
+}
+
+func TestValidateInstallDisk(t *testing.T) {
+	root := path.Root("install").AtName("disk_selector")
+	none := types.String{Null: true}
+
+	tests := []struct {
+		name     string
+		disk     types.String
+		selector *InstallDiskSelector
+		errors   []path.Path
+	}{
+		{name: "disk", disk: Wraps("/dev/sda")},
+		{name: "selector", disk: none, selector: &InstallDiskSelector{Size: Wraps(">= 1TB"), Model: Wraps("WDC*"), Type: none}},
+		{name: "unknown selector", disk: none, selector: &InstallDiskSelector{Type: types.String{Unknown: true}}},
+		{name: "disk and selector", disk: Wraps("/dev/sda"), selector: &InstallDiskSelector{Type: Wraps("ssd")}, errors: []path.Path{root}},
+		{name: "empty selector", disk: none, selector: &InstallDiskSelector{Type: none, BusPath: none}, errors: []path.Path{root}},
+	}
+
+	for _, tc := range tests {
+		checkErrorPaths(t, tc.name, ValidateInstallDisk(tc.disk, tc.selector, root), tc.errors)
+	}
+}
+
+func TestValidateKubelet(t *testing.T) {
+	root := path.Root("kubelet")
+
+	kubelet := &KubeletConfig{
+		ExtraArgs: map[string]types.String{"node-labels": Wraps("zone=a"), "register-with-taints": Wraps("dedicated:NoSchedule")},
+		Labels:    map[string]types.String{"zone": Wraps("a")},
+	}
+
+	checkErrorPaths(t, "kubelet", ValidateKubelet(kubelet, root), []path.Path{root.AtName("extra_args").AtMapKey("node-labels")})
+}
+
+func TestValidateAPIServer(t *testing.T) {
+	root := path.Root("apiserver")
+
+	apiServer := &APIServerConfig{
+		AdmissionPlugins: []AdmissionPluginConfig{{Name: Wraps("EventRateLimit")}, {Name: Wraps("PodSecurity")}},
+		PodSecurity:      PodSecurityDefaults(),
+	}
+
+	checkErrorPaths(t, "apiserver", ValidateAPIServer(apiServer, root), []path.Path{root.AtName("admission_control").AtListIndex(1)})
+
+	apiServer = &APIServerConfig{
+		ExtraArgs: map[string]types.String{
+			"audit-policy-file": Wraps("/etc/kubernetes/audit-policy.yaml"),
+			"oidc-client-id":    Wraps("kubernetes"),
+		},
+		AuditPolicy: &AuditPolicyConfig{Rules: []AuditRule{{Level: Wraps("Metadata")}}, Policy: Wraps("kind: Policy\n")},
+		OIDC:        &OIDCConfig{IssuerURL: Wraps("https://accounts.example.com")},
+	}
+
+	checkErrorPaths(t, "authentication", ValidateAPIServer(apiServer, root), []path.Path{
+		root.AtName("audit_policy").AtName("policy"),
+		root.AtName("extra_args").AtMapKey("audit-policy-file"),
+		root.AtName("extra_args").AtMapKey("oidc-client-id"),
+	})
+
+	apiServer = &APIServerConfig{AuditPolicy: &AuditPolicyConfig{Policy: types.String{Null: true}}}
+	checkErrorPaths(t, "empty audit policy", ValidateAPIServer(apiServer, root), []path.Path{root.AtName("audit_policy")})
+}
+
+func checkErrorPaths(t *testing.T, name string, diags diag.Diagnostics, expected []path.Path) {
+	t.Helper()
+
+	if diags.ErrorsCount() != len(expected) {
+		t.Errorf("%s: expected %d errors, got %v", name, len(expected), diags)
+		return
+	}
+
+	for i, d := range diags.Errors() {
+		withPath, ok := d.(diag.DiagnosticWithPath)
+		if !ok || !withPath.Path().Equal(expected[i]) {
+			t.Errorf("%s: expected error at %s, got %v", name, expected[i], d)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package datatypes
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValidators(t *testing.T) {
+	base, err := json.Marshal(InputBundleExample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		validator tfsdk.AttributeValidator
+		valid     []string
+		invalid   []string
+	}{
+		{"mac", ValidateMAC(), []string{"52:54:00:12:34:56"}, []string{"52:54:00:12:34", "eth0"}},
+		{"cidr", ValidateCIDR(), []string{"192.168.1.10/24", "fd00::1/64"}, []string{"192.168.1.10", "192.168.1.0/33"}},
+		{"subnet", ValidateSubnet(), []string{"10.0.0.0/8", "!10.0.0.3/32"}, []string{"10.0.0.3"}},
+		{"ip", ValidateIP(), []string{"10.0.0.1", "fd00::1"}, []string{"10.0.0.1/24", "node1"}},
+		{"image", ValidateImage(), []string{"ghcr.io/siderolabs/installer:v1.1.1", "busybox", "registry.local:5000/talos/kubelet:v1.24.2"}, []string{"Ghcr.io/Installer", "image:", "ghcr.io/installer:tag with space"}},
+		{"wireguard key", ValidateWireguardKey(), []string{"sMDT0Eq6Yz8sl+zUmZPpxBs3MFwSvr8dL8MtH4DkeXI="}, []string{"not a key"}},
+		{"domain", ValidateDomain(), []string{"node-1", "node-1.cluster.local"}, []string{"Node_1", "-node", "node."}},
+		{"endpoint", ValidateEndpoint(), []string{"10.0.0.1:51820", "peer.example.com:51820", "[fd00::1]:51820"}, []string{"10.0.0.1", "peer.example.com:"}},
+		{"url", ValidateURL("tcp", "udp"), []string{"udp://127.0.0.1:12345", "tcp://logs.example.com:514"}, []string{"http://127.0.0.1:12345", "127.0.0.1:12345"}},
+		{"one of", ValidateOneOf("auto", "no_reboot"), []string{"auto", "no_reboot"}, []string{"Auto", "reboot", ""}},
+		{"duration", ValidateDuration(), []string{"1h", "8760h", "10m30s"}, []string{"1 year", "10"}},
+		{"disk size", ValidateDiskSize(), []string{"4GB", "> 1TB", "<= 2TB"}, []string{"big", "~ 1TB"}},
+		{"disk type", ValidateDiskType(), []string{"ssd", "nvme"}, []string{"SSD", "floppy"}},
+		{"label key", ValidateLabelKey(), []string{"node-role.kubernetes.io/worker", "zone", "Node_1.a"}, []string{"Example.com/zone", "-zone", "a/b/c", ""}},
+		{"label value", ValidateLabelValue(), []string{"", "eu-west-1a", "A.b_c"}, []string{"a b", "value-", "a,b"}},
+		{"taint effect", ValidateTaintEffect(), []string{"NoSchedule", "NoExecute"}, []string{"noschedule", "Evict"}},
+		{"pod security level", ValidatePodSecurityLevel(), []string{"baseline", "restricted"}, []string{"Baseline", "strict"}},
+		{"pod security version", ValidatePodSecurityVersion(), []string{"latest", "v1.24"}, []string{"1.24", "v1.24.2"}},
+		{"audit level", ValidateAuditLevel(), []string{"None", "RequestResponse"}, []string{"metadata", "Response"}},
+		{"audit stage", ValidateAuditStage(), []string{"RequestReceived", "Panic"}, []string{"requestreceived", "ResponseSent"}},
+		{"certificate", ValidateCertificate(), []string{string(InputBundleExample.Certs.Etcd.Crt)}, []string{"not a certificate", string(InputBundleExample.Certs.Etcd.Key)}},
+		{"yaml", ValidateYAML(), []string{"kind: Configuration\n", "a: [1, 2]"}, []string{"kind: [", "a: b: c"}},
+		{"base config", ValidateBaseConfig(), []string{string(base)}, []string{"{}", "not json"}},
+	}
+
+	for _, tc := range tests {
+		for _, v := range tc.valid {
+			if diags := validate(tc.validator, types.String{Value: v}); diags.HasError() {
+				t.Errorf("%s: %q rejected: %v", tc.name, v, diags)
+			}
+		}
+
+		for _, v := range tc.invalid {
+			if diags := validate(tc.validator, types.String{Value: v}); !diags.HasError() {
+				t.Errorf("%s: %q accepted", tc.name, v)
+			}
+		}
+	}
+}
+
+func TestValidatorElements(t *testing.T) {
+	list := types.List{
+		ElemType: types.StringType,
+		Elems: []attr.Value{
+			types.String{Value: "10.0.0.1"},
+			types.String{Unknown: true},
+			types.String{Value: "node1"},
+		},
+	}
+
+	diags := validate(ValidateIP(), list)
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("expected a single error, got %v", diags)
+	}
+
+	withPath, ok := diags[0].(diag.DiagnosticWithPath)
+	if !ok {
+		t.Fatalf("expected an attribute error, got %v", diags[0])
+	}
+	if got := withPath.Path(); !got.Equal(path.Root("nameservers").AtListIndex(2)) {
+		t.Errorf("expected the error at the invalid element, got %s", got)
+	}
+
+	if diags := validate(ValidateIP(), types.String{Null: true}); diags.HasError() {
+		t.Errorf("null value rejected: %v", diags)
+	}
+}
+
+func TestValidatorKeys(t *testing.T) {
+	labels := types.Map{
+		ElemType: types.StringType,
+		Elems: map[string]attr.Value{
+			"zone":         types.String{Value: "a"},
+			"Example.com/": types.String{Value: "b"},
+		},
+	}
+
+	diags := validate(ValidateLabelKeys(), labels)
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("expected a single error, got %v", diags)
+	}
+
+	withPath, ok := diags[0].(diag.DiagnosticWithPath)
+	if !ok || !withPath.Path().Equal(path.Root("nameservers").AtMapKey("Example.com/")) {
+		t.Errorf("expected the error at the invalid key, got %v", diags[0])
+	}
+}
+
+func validate(v tfsdk.AttributeValidator, value attr.Value) diag.Diagnostics {
+	resp := &tfsdk.ValidateAttributeResponse{}
+	v.Validate(context.Background(), tfsdk.ValidateAttributeRequest{
+		AttributePath:   path.Root("nameservers"),
+		AttributeConfig: value,
+	}, resp)
+
+	return resp.Diagnostics
+}
@@ -54,8 +54,16 @@ func readExtraVolumes(mounts []VolumeMount, talosMounts VolumeMounts) []VolumeMo
 func (talosVolumeMounts TalosAPIServerMounts) ReadFunc() []ConfigReadFunc {
 	funs := []ConfigReadFunc{
 		func(planConfig *TalosConfig) error {
-			planConfig.APIServer.ExtraVolumes = readExtraVolumes(planConfig.APIServer.ExtraVolumes,
-				talosVolumeMounts.VolumeMounts)
+			// The mount of the files rendered for the apiserver isn't read, it's configured along with them.
+			var mounts VolumeMounts
+			for _, mount := range talosVolumeMounts.VolumeMounts {
+				if mount.HostPath() != apiServerConfigDir {
+					mounts = append(mounts, mount)
+				}
+			}
+			if len(mounts) > 0 {
+				planConfig.APIServer.ExtraVolumes = readExtraVolumes(planConfig.APIServer.ExtraVolumes, mounts)
+			}
 			return nil
 		},
 	}
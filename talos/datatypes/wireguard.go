@@ -1,8 +1,12 @@
 package datatypes
 
 import (
+	"fmt"
+	"net"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
 	"github.com/talos-systems/talos/pkg/machinery/config"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
@@ -12,10 +16,10 @@ import (
 func (planWireguard Wireguard) Data() (interface{}, error) {
 	wireguard := &v1alpha1.DeviceWireguardConfig{}
 
-	for _, planPeer := range planWireguard.Peers {
+	for i, planPeer := range planWireguard.Peers {
 		peer, err := planPeer.Data()
 		if err != nil {
-			return &v1alpha1.DeviceWireguardConfig{}, nil
+			return nil, fmt.Errorf("wireguard peer %d: %w", i, err)
 		}
 		wireguard.WireguardPeers = append(wireguard.WireguardPeers, peer.(*v1alpha1.DeviceWireguardPeer))
 	}
@@ -46,12 +50,19 @@ func readWireguardPeer(peer config.WireguardPeer) (out WireguardPeer) {
 
 // Data copies data from terraform state types to talos types.
 func (planPeer WireguardPeer) Data() (interface{}, error) {
+	if _, err := wgtypes.ParseKey(planPeer.PublicKey.Value); err != nil {
+		return nil, fmt.Errorf("invalid public key %q: %w", planPeer.PublicKey.Value, err)
+	}
+
 	peer := &v1alpha1.DeviceWireguardPeer{
 		WireguardPublicKey: planPeer.PublicKey.Value,
-		WireguardEndpoint:  planPeer.Endpoint.Value,
 	}
+	setString(planPeer.Endpoint, &peer.WireguardEndpoint)
 
 	for _, ip := range planPeer.AllowedIPs {
+		if _, _, err := net.ParseCIDR(ip.Value); err != nil {
+			return nil, fmt.Errorf("invalid allowed IP %q: %w", ip.Value, err)
+		}
 		peer.WireguardAllowedIPs = append(peer.WireguardAllowedIPs, ip.Value)
 	}
 
@@ -81,3 +92,31 @@ func readWireguardConfig(config config.WireguardConfig) (out *Wireguard, err err
 
 	return
 }
+
+// GenerateKeys fills in the interface's key pair. A private key which isn't configured is taken from prior, the
+// interface's previous state, so it stays the same across plans. A new one is only generated when there is none.
+func (planWireguard *Wireguard) GenerateKeys(prior *Wireguard) error {
+	if planWireguard.PrivateKey.Unknown {
+		return nil
+	}
+
+	if planWireguard.PrivateKey.Null || planWireguard.PrivateKey.Value == "" {
+		if prior != nil && !prior.PrivateKey.Null && !prior.PrivateKey.Unknown && prior.PrivateKey.Value != "" {
+			planWireguard.PrivateKey = types.String{Value: prior.PrivateKey.Value}
+		} else {
+			key, err := wgtypes.GeneratePrivateKey()
+			if err != nil {
+				return err
+			}
+			planWireguard.PrivateKey = types.String{Value: key.String()}
+		}
+	}
+
+	key, err := wgtypes.ParseKey(planWireguard.PrivateKey.Value)
+	if err != nil {
+		return err
+	}
+	planWireguard.PublicKey = types.String{Value: key.PublicKey().String()}
+
+	return nil
+}
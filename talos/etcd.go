@@ -0,0 +1,274 @@
+package talos
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// etcdMembers returns the etcd members known to the node a client is connected to.
+func etcdMembers(ctx context.Context, client machine.MachineServiceClient) ([]*machine.EtcdMember, error) {
+	resp, err := client.EtcdMemberList(ctx, &machine.EtcdMemberListRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting etcd members: %w", err)
+	}
+
+	if len(resp.Messages) < 1 {
+		return nil, fmt.Errorf("invalid message count from the etcd member list request. Expected > 1 but got %d", len(resp.Messages))
+	}
+
+	return resp.Messages[len(resp.Messages)-1].Members, nil
+}
+
+// memberHealthTimeout is how long each etcd member is given to report its health to the quorum guard.
+var memberHealthTimeout = 30 * time.Second
+
+// quorumSafe reports whether an etcd cluster of members voting members keeps quorum with only healthy of them, after
+// one member is removed if removing is set. The healthy count excludes the member being removed or rebooted.
+// A single member cluster is always safe, destroying or rebooting its only member is deliberate.
+func quorumSafe(members, healthy int, removing bool) (required int, safe bool) {
+	if members <= 1 {
+		return 0, true
+	}
+
+	if removing {
+		members--
+	}
+
+	required = members/2 + 1
+	return required, healthy >= required
+}
+
+// memberHost returns the address of the node running an etcd member, taken from its client URLs.
+func memberHost(member *machine.EtcdMember) string {
+	for _, clientURL := range append(append([]string{}, member.ClientUrls...), member.PeerUrls...) {
+		if u, err := url.Parse(clientURL); err == nil && u.Hostname() != "" {
+			return u.Hostname()
+		}
+	}
+
+	return ""
+}
+
+// memberHealthy checks that etcd is running and healthy on the node at host.
+func memberHealthy(ctx context.Context, input generate.Input, host string) error {
+	ctx, cancel := context.WithTimeout(ctx, memberHealthTimeout)
+	defer cancel()
+
+	conn, err := secureConn(ctx, input, net.JoinHostPort(host, strconv.Itoa(talosPort)))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return serviceHealthy(ctx, machine.NewMachineServiceClient(conn), "etcd")
+}
+
+// memberRestartTimeout is how long a rebooted control node is given to rejoin etcd as a healthy member.
+var memberRestartTimeout = 10 * time.Minute
+
+// lockEtcd serializes the operations which remove or reboot an etcd member across the provider's resources, so the
+// quorum checked before one of them can't be lost to another running in parallel. Each operation holds the lock from
+// its quorum check until the member has left etcd or is healthy again. The returned function releases it.
+func (p provider) lockEtcd(ctx context.Context) func() {
+	tflog.Debug(ctx, "Waiting for other etcd member operations to complete")
+	p.etcdLock.Lock()
+
+	return p.etcdLock.Unlock
+}
+
+// etcdHealthChange returns when the health of etcd on the node at ip last changed, as reported by the node.
+func etcdHealthChange(ctx context.Context, input generate.Input, ip string) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, memberHealthTimeout)
+	defer cancel()
+
+	conn, err := secureConn(ctx, input, net.JoinHostPort(ip, strconv.Itoa(talosPort)))
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer conn.Close()
+
+	resp, err := machine.NewMachineServiceClient(conn).ServiceList(ctx, &emptypb.Empty{})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, msg := range resp.Messages {
+		for _, svc := range msg.Services {
+			if svc.Id == "etcd" && svc.Health != nil && svc.Health.LastChange != nil {
+				return svc.Health.LastChange.AsTime(), nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("service etcd not found")
+}
+
+// waitMemberRestarted waits until etcd on the rebooted node at ip is healthy again. Its health must have changed since
+// before, so a member which hasn't gone down yet isn't mistaken for one which is back.
+func waitMemberRestarted(ctx context.Context, input generate.Input, ip string, before time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, memberRestartTimeout)
+	defer cancel()
+
+	for {
+		err := func() error {
+			changed, err := etcdHealthChange(ctx, input, ip)
+			if err != nil {
+				return err
+			}
+			if !changed.After(before) {
+				return fmt.Errorf("etcd hasn't restarted yet")
+			}
+
+			return memberHealthy(ctx, input, ip)
+		}()
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for etcd on %s to become healthy after the reboot: %w", ip, err)
+		default:
+			tflog.Info(ctx, "Waiting for etcd on "+ip+" to become healthy, reason "+err.Error())
+			time.Sleep(componentPollInterval)
+		}
+	}
+}
+
+// checkQuorum refuses to destroy or reboot the control node at ip if etcd would be left without a quorum of healthy
+// members. Membership is read from the node itself, or from the first of its peers that can be reached. Callers hold
+// the lock of lockEtcd until the operation the check guards is complete.
+func checkQuorum(ctx context.Context, input generate.Input, ip string, hostname string, peers []string, removing bool) error {
+	var (
+		members []*machine.EtcdMember
+		err     error
+	)
+
+	for _, node := range append([]string{ip}, peers...) {
+		members, err = func() ([]*machine.EtcdMember, error) {
+			ctx, cancel := context.WithTimeout(ctx, memberHealthTimeout)
+			defer cancel()
+
+			conn, err := secureConn(ctx, input, net.JoinHostPort(node, strconv.Itoa(talosPort)))
+			if err != nil {
+				return nil, err
+			}
+			defer conn.Close()
+
+			return etcdMembers(ctx, machine.NewMachineServiceClient(conn))
+		}()
+		if err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to determine etcd membership: %w", err)
+	}
+
+	voting, healthy, isMember := 0, 0, false
+	unhealthy := []string{}
+	for _, member := range members {
+		if member.IsLearner {
+			continue
+		}
+		voting++
+
+		if member.Hostname == hostname {
+			isMember = true
+			continue
+		}
+
+		if err := memberHealthy(ctx, input, memberHost(member)); err != nil {
+			unhealthy = append(unhealthy, member.Hostname+" ("+err.Error()+")")
+			continue
+		}
+		healthy++
+	}
+
+	required, safe := quorumSafe(voting, healthy, removing && isMember)
+	if safe {
+		return nil
+	}
+
+	return fmt.Errorf("%d of %d etcd members would remain healthy but %d are needed for quorum. Unhealthy members: %v",
+		healthy, voting, required, unhealthy)
+}
+
+// leaveEtcd removes a control node's member from etcd before it is destroyed. The node is asked to leave the cluster
+// itself, and if it can't be reached its member is removed through the first of its peers that can be.
+// The last member of a cluster is left alone, as the cluster is being destroyed along with it.
+func leaveEtcd(ctx context.Context, input generate.Input, ip string, hostname string, peers []string) error {
+	err := func() error {
+		conn, err := secureConn(ctx, input, net.JoinHostPort(ip, strconv.Itoa(talosPort)))
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		client := machine.NewMachineServiceClient(conn)
+		members, err := etcdMembers(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		if len(members) <= 1 {
+			return nil
+		}
+
+		_, err = client.EtcdLeaveCluster(ctx, &machine.EtcdLeaveClusterRequest{})
+		return err
+	}()
+	if err == nil {
+		return nil
+	}
+
+	tflog.Warn(ctx, "Unable to leave etcd from "+ip+", removing member "+hostname+" through a peer. Reason "+err.Error())
+
+	for _, peer := range peers {
+		if peer == ip {
+			continue
+		}
+
+		err = func() error {
+			conn, err := secureConn(ctx, input, net.JoinHostPort(peer, strconv.Itoa(talosPort)))
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			client := machine.NewMachineServiceClient(conn)
+			members, err := etcdMembers(ctx, client)
+			if err != nil {
+				return err
+			}
+
+			for _, member := range members {
+				if member.Hostname == hostname {
+					_, err = client.EtcdRemoveMember(ctx, &machine.EtcdRemoveMemberRequest{Member: hostname})
+					return err
+				}
+			}
+
+			// The member has already been removed.
+			return nil
+		}()
+		if err == nil {
+			return nil
+		}
+
+		tflog.Warn(ctx, "Unable to remove etcd member "+hostname+" through "+peer+". Reason "+err.Error())
+	}
+
+	return fmt.Errorf("node %s is unreachable and its etcd member %s could not be removed through any peer: %w", ip, hostname, err)
+}
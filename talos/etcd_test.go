@@ -0,0 +1,71 @@
+package talos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+)
+
+// TestQuorumSafe checks which destructive operations the quorum guard lets through.
+func TestQuorumSafe(t *testing.T) {
+	for _, tc := range []struct {
+		members, healthy int
+		removing         bool
+		safe             bool
+	}{
+		{members: 1, healthy: 0, removing: true, safe: true},
+		{members: 1, healthy: 0, removing: false, safe: true},
+		{members: 3, healthy: 2, removing: false, safe: true},
+		{members: 3, healthy: 1, removing: false, safe: false},
+		{members: 3, healthy: 1, removing: true, safe: false},
+		{members: 3, healthy: 2, removing: true, safe: true},
+		{members: 2, healthy: 1, removing: true, safe: true},
+		{members: 2, healthy: 1, removing: false, safe: false},
+		{members: 5, healthy: 3, removing: false, safe: true},
+		{members: 5, healthy: 2, removing: true, safe: false},
+	} {
+		if _, safe := quorumSafe(tc.members, tc.healthy, tc.removing); safe != tc.safe {
+			t.Errorf("members %d, healthy %d, removing %v: expected safe %v", tc.members, tc.healthy, tc.removing, tc.safe)
+		}
+	}
+}
+
+func TestMemberHost(t *testing.T) {
+	member := &machine.EtcdMember{
+		ClientUrls: []string{"https://10.0.0.2:2379"},
+		PeerUrls:   []string{"https://10.0.0.3:2380"},
+	}
+	if host := memberHost(member); host != "10.0.0.2" {
+		t.Errorf("expected host from client URL, got %q", host)
+	}
+
+	member.ClientUrls = nil
+	if host := memberHost(member); host != "10.0.0.3" {
+		t.Errorf("expected host from peer URL, got %q", host)
+	}
+}
+
+// TestLockEtcd checks that resources, which each hold a copy of the provider, share its etcd lock.
+func TestLockEtcd(t *testing.T) {
+	p := New("test")().(*provider)
+	first, second := *p, *p
+
+	unlock := first.lockEtcd(context.Background())
+
+	locked := make(chan struct{})
+	go func() {
+		defer second.lockEtcd(context.Background())()
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("expected the etcd lock to be held across copies of the provider")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	<-locked
+}
@@ -0,0 +1,384 @@
+package talos
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	stdx509 "crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/talos-systems/crypto/x509"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+)
+
+// kubernetesClient is a minimal client for the handful of Kubernetes API calls the provider needs to make.
+// It authenticates as a cluster administrator using a short lived client certificate signed by the cluster's
+// Kubernetes CA from the base_config.
+type kubernetesClient struct {
+	endpoint string
+	client   *http.Client
+}
+
+func newKubernetesClient(input generate.Input) (*kubernetesClient, error) {
+	if input.Certs == nil || input.Certs.K8s == nil {
+		return nil, fmt.Errorf("base_config does not contain a Kubernetes CA")
+	}
+
+	ca, err := x509.NewCertificateAuthorityFromCertificateAndKey(input.Certs.K8s)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load Kubernetes CA: %w", err)
+	}
+
+	keyPair, err := x509.NewKeyPair(ca,
+		x509.CommonName("admin"),
+		x509.Organization("system:masters"),
+		x509.NotAfter(time.Now().Add(time.Hour)),
+		x509.ExtKeyUsage([]stdx509.ExtKeyUsage{stdx509.ExtKeyUsageClientAuth}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate Kubernetes admin certificate: %w", err)
+	}
+
+	certPool := stdx509.NewCertPool()
+	if ok := certPool.AppendCertsFromPEM(input.Certs.K8s.Crt); !ok {
+		return nil, fmt.Errorf("unable to append certs from PEM")
+	}
+
+	return &kubernetesClient{
+		endpoint: strings.TrimSuffix(input.GetControlPlaneEndpoint(), "/"),
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					RootCAs:      certPool,
+					Certificates: []tls.Certificate{*keyPair.Certificate},
+				},
+			},
+		},
+	}, nil
+}
+
+// do performs a request against the Kubernetes API and decodes the response into out if it is non nil.
+func (k *kubernetesClient) do(ctx context.Context, method, path, contentType string, body io.Reader, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, k.endpoint+path, body)
+	if err != nil {
+		return err
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(resp.Body)
+		return &kubernetesError{StatusCode: resp.StatusCode, Message: string(msg)}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// kubernetesError is returned when the Kubernetes API responds with a non 2xx status code.
+type kubernetesError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *kubernetesError) Error() string {
+	return fmt.Sprintf("kubernetes API returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// kubernetesNode is the subset of a Kubernetes Node object used by the provider.
+type kubernetesNode struct {
+	Spec struct {
+		Unschedulable bool `json:"unschedulable"`
+	} `json:"spec"`
+	Status struct {
+		Conditions []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+		} `json:"conditions"`
+		Addresses []struct {
+			Type    string `json:"type"`
+			Address string `json:"address"`
+		} `json:"addresses"`
+	} `json:"status"`
+}
+
+// controlPlaneLabel marks the Node objects of control plane nodes.
+const controlPlaneLabel = "node-role.kubernetes.io/control-plane"
+
+// internalAddresses returns the addresses the node is reachable at from within the cluster.
+func (n *kubernetesNode) internalAddresses() []string {
+	addresses := []string{}
+	for _, address := range n.Status.Addresses {
+		if address.Type == "InternalIP" {
+			addresses = append(addresses, address.Address)
+		}
+	}
+
+	return addresses
+}
+
+func (k *kubernetesClient) getNode(ctx context.Context, name string) (*kubernetesNode, error) {
+	node := &kubernetesNode{}
+	if err := k.do(ctx, http.MethodGet, "/api/v1/nodes/"+url.PathEscape(name), "", nil, node); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// controlPlaneAddresses returns the internal addresses of the cluster's control plane nodes.
+func (k *kubernetesClient) controlPlaneAddresses(ctx context.Context) ([]string, error) {
+	list := struct {
+		Items []kubernetesNode `json:"items"`
+	}{}
+
+	query := url.Values{"labelSelector": []string{controlPlaneLabel}}
+	if err := k.do(ctx, http.MethodGet, "/api/v1/nodes?"+query.Encode(), "", nil, &list); err != nil {
+		return nil, err
+	}
+
+	addresses := []string{}
+	for _, node := range list.Items {
+		addresses = append(addresses, node.internalAddresses()...)
+	}
+
+	return addresses, nil
+}
+
+// nodeReady returns nil once the named node reports the Ready condition.
+func (k *kubernetesClient) nodeReady(ctx context.Context, name string) error {
+	node, err := k.getNode(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == "Ready" {
+			if condition.Status == "True" {
+				return nil
+			}
+
+			return fmt.Errorf("node %s is not ready", name)
+		}
+	}
+
+	return fmt.Errorf("node %s has not reported a Ready condition", name)
+}
+
+// kubernetesDaemonSet is the subset of a Kubernetes DaemonSet object needed to follow its rollout.
+type kubernetesDaemonSet struct {
+	Metadata struct {
+		Generation int64 `json:"generation"`
+	} `json:"metadata"`
+	Spec struct {
+		Template struct {
+			Spec struct {
+				Containers []struct {
+					Image string `json:"image"`
+				} `json:"containers"`
+			} `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+	Status struct {
+		ObservedGeneration     int64 `json:"observedGeneration"`
+		DesiredNumberScheduled int   `json:"desiredNumberScheduled"`
+		UpdatedNumberScheduled int   `json:"updatedNumberScheduled"`
+		NumberAvailable        int   `json:"numberAvailable"`
+	} `json:"status"`
+}
+
+// daemonSetRolledOut returns nil once the named DaemonSet runs image and every one of its pods has been updated
+// and is available.
+func (k *kubernetesClient) daemonSetRolledOut(ctx context.Context, namespace, name, image string) error {
+	ds := &kubernetesDaemonSet{}
+	path := "/apis/apps/v1/namespaces/" + url.PathEscape(namespace) + "/daemonsets/" + url.PathEscape(name)
+	if err := k.do(ctx, http.MethodGet, path, "", nil, ds); err != nil {
+		return err
+	}
+
+	for _, container := range ds.Spec.Template.Spec.Containers {
+		if container.Image != image {
+			return fmt.Errorf("daemonset %s is configured with image %s", name, container.Image)
+		}
+	}
+
+	status := ds.Status
+	switch {
+	case status.ObservedGeneration < ds.Metadata.Generation:
+		return fmt.Errorf("daemonset %s update has not been observed yet", name)
+	case status.UpdatedNumberScheduled < status.DesiredNumberScheduled:
+		return fmt.Errorf("daemonset %s has updated %d of %d pods", name, status.UpdatedNumberScheduled, status.DesiredNumberScheduled)
+	case status.NumberAvailable < status.DesiredNumberScheduled:
+		return fmt.Errorf("daemonset %s has %d of %d pods available", name, status.NumberAvailable, status.DesiredNumberScheduled)
+	}
+
+	return nil
+}
+
+// kubernetesPod is the subset of a Kubernetes Pod object needed to drain a node.
+type kubernetesPod struct {
+	Metadata struct {
+		Name            string            `json:"name"`
+		Namespace       string            `json:"namespace"`
+		Annotations     map[string]string `json:"annotations"`
+		OwnerReferences []struct {
+			Kind string `json:"kind"`
+		} `json:"ownerReferences"`
+	} `json:"metadata"`
+}
+
+// mirrorPodAnnotation marks the API server's copies of static pods, which can't be evicted.
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// drainTimeout is how long the pods on a node are given to be evicted.
+var drainTimeout = 5 * time.Minute
+
+// evictable reports whether a pod should be evicted when draining its node. Pods managed by a DaemonSet would be
+// recreated on the node straight away and static pods are removed along with the node.
+func (p *kubernetesPod) evictable() bool {
+	if _, ok := p.Metadata.Annotations[mirrorPodAnnotation]; ok {
+		return false
+	}
+
+	for _, owner := range p.Metadata.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (k *kubernetesClient) evictablePods(ctx context.Context, name string) ([]kubernetesPod, error) {
+	list := struct {
+		Items []kubernetesPod `json:"items"`
+	}{}
+
+	query := url.Values{"fieldSelector": []string{"spec.nodeName=" + name}}
+	if err := k.do(ctx, http.MethodGet, "/api/v1/pods?"+query.Encode(), "", nil, &list); err != nil {
+		return nil, err
+	}
+
+	pods := []kubernetesPod{}
+	for _, pod := range list.Items {
+		if pod.evictable() {
+			pods = append(pods, pod)
+		}
+	}
+
+	return pods, nil
+}
+
+// cordon marks the named node unschedulable.
+func (k *kubernetesClient) cordon(ctx context.Context, name string) error {
+	patch := strings.NewReader(`{"spec":{"unschedulable":true}}`)
+	return k.do(ctx, http.MethodPatch, "/api/v1/nodes/"+url.PathEscape(name), "application/merge-patch+json", patch, nil)
+}
+
+// drain evicts the pods running on the named node and waits for them to be gone. Evictions refused because of a
+// pod disruption budget are retried until drainTimeout elapses.
+func (k *kubernetesClient) drain(ctx context.Context, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, drainTimeout)
+	defer cancel()
+
+	for {
+		pods, err := k.evictablePods(ctx, name)
+		if err == nil && len(pods) == 0 {
+			return nil
+		}
+
+		for _, pod := range pods {
+			if err = k.evict(ctx, pod); err != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if err == nil {
+				err = fmt.Errorf("%d pods remaining", len(pods))
+			}
+			return fmt.Errorf("timed out draining node %s: %w", name, err)
+		default:
+			time.Sleep(componentPollInterval)
+		}
+	}
+}
+
+func (k *kubernetesClient) evict(ctx context.Context, pod kubernetesPod) error {
+	eviction, err := json.Marshal(map[string]any{
+		"apiVersion": "policy/v1",
+		"kind":       "Eviction",
+		"metadata": map[string]string{
+			"name":      pod.Metadata.Name,
+			"namespace": pod.Metadata.Namespace,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	path := "/api/v1/namespaces/" + url.PathEscape(pod.Metadata.Namespace) + "/pods/" + url.PathEscape(pod.Metadata.Name) + "/eviction"
+	err = k.do(ctx, http.MethodPost, path, "application/json", bytes.NewReader(eviction), nil)
+
+	// The pod is already gone.
+	if kerr, ok := err.(*kubernetesError); ok && kerr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	return err
+}
+
+// apiUnreachable reports whether err is due to the Kubernetes API not being reachable, rather than to the API
+// refusing a request.
+func apiUnreachable(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// drainNode cordons and drains the named node. Nodes which never joined, or have already been deleted, are ignored.
+func (k *kubernetesClient) drainNode(ctx context.Context, name string) error {
+	if err := k.cordon(ctx, name); err != nil {
+		if kerr, ok := err.(*kubernetesError); ok && kerr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+
+		return fmt.Errorf("unable to cordon node %s: %w", name, err)
+	}
+
+	return k.drain(ctx, name)
+}
+
+// deleteNode deletes the named node's Node object. Nodes which have already been deleted are ignored.
+func (k *kubernetesClient) deleteNode(ctx context.Context, name string) error {
+	err := k.do(ctx, http.MethodDelete, "/api/v1/nodes/"+url.PathEscape(name), "", nil, nil)
+	if kerr, ok := err.(*kubernetesError); ok && kerr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to delete node %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package talos
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPodEvictable(t *testing.T) {
+	for name, tc := range map[string]struct {
+		pod       string
+		evictable bool
+	}{
+		"deployment": {
+			pod:       `{"metadata":{"name":"a","ownerReferences":[{"kind":"ReplicaSet"}]}}`,
+			evictable: true,
+		},
+		"bare": {
+			pod:       `{"metadata":{"name":"a"}}`,
+			evictable: true,
+		},
+		"daemonset": {
+			pod:       `{"metadata":{"name":"a","ownerReferences":[{"kind":"DaemonSet"}]}}`,
+			evictable: false,
+		},
+		"mirror": {
+			pod:       `{"metadata":{"name":"a","annotations":{"kubernetes.io/config.mirror":"abc"}}}`,
+			evictable: false,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			pod := kubernetesPod{}
+			if err := json.Unmarshal([]byte(tc.pod), &pod); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := pod.evictable(); got != tc.evictable {
+				t.Errorf("expected evictable %v, got %v", tc.evictable, got)
+			}
+		})
+	}
+}
+
+// TestControlPlaneAddresses checks that the internal addresses of the control plane nodes are listed, and that an
+// API which can't be reached is told apart from one refusing a request.
+func TestControlPlaneAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("labelSelector") != controlPlaneLabel {
+			http.Error(w, "unexpected selector", http.StatusBadRequest)
+			return
+		}
+
+		w.Write([]byte(`{"items":[
+			{"status":{"addresses":[{"type":"InternalIP","address":"10.0.0.2"},{"type":"Hostname","address":"cp-1"}]}},
+			{"status":{"addresses":[{"type":"InternalIP","address":"10.0.0.3"}]}}
+		]}`))
+	}))
+
+	k8s := &kubernetesClient{endpoint: srv.URL, client: srv.Client()}
+	addresses, err := k8s.controlPlaneAddresses(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"10.0.0.2", "10.0.0.3"}; !reflect.DeepEqual(addresses, expected) {
+		t.Errorf("expected addresses %v, got %v", expected, addresses)
+	}
+
+	if err := k8s.cordon(context.Background(), "cp-1"); err == nil || apiUnreachable(err) {
+		t.Errorf("expected a refused request not to be reported as an unreachable API, got %v", err)
+	}
+
+	srv.Close()
+	if err := k8s.drainNode(context.Background(), "cp-1"); !apiUnreachable(err) {
+		t.Errorf("expected an unreachable API to be reported, got %v", err)
+	}
+}
+
+// TestDaemonSetRolledOut checks that a DaemonSet is only considered rolled out once every pod runs the new image.
+func TestDaemonSetRolledOut(t *testing.T) {
+	const image = "k8s.gcr.io/kube-proxy:v1.24.3"
+
+	for _, tc := range []struct {
+		name      string
+		daemonSet string
+		rolledOut bool
+	}{
+		{
+			name:      "rolled out",
+			daemonSet: `{"metadata":{"generation":2},"spec":{"template":{"spec":{"containers":[{"image":"` + image + `"}]}}},"status":{"observedGeneration":2,"desiredNumberScheduled":3,"updatedNumberScheduled":3,"numberAvailable":3}}`,
+			rolledOut: true,
+		},
+		{
+			name:      "old image",
+			daemonSet: `{"metadata":{"generation":1},"spec":{"template":{"spec":{"containers":[{"image":"k8s.gcr.io/kube-proxy:v1.24.2"}]}}},"status":{"observedGeneration":1,"desiredNumberScheduled":3,"updatedNumberScheduled":3,"numberAvailable":3}}`,
+		},
+		{
+			name:      "not observed",
+			daemonSet: `{"metadata":{"generation":2},"spec":{"template":{"spec":{"containers":[{"image":"` + image + `"}]}}},"status":{"observedGeneration":1,"desiredNumberScheduled":3,"updatedNumberScheduled":3,"numberAvailable":3}}`,
+		},
+		{
+			name:      "updating",
+			daemonSet: `{"metadata":{"generation":2},"spec":{"template":{"spec":{"containers":[{"image":"` + image + `"}]}}},"status":{"observedGeneration":2,"desiredNumberScheduled":3,"updatedNumberScheduled":2,"numberAvailable":3}}`,
+		},
+		{
+			name:      "unavailable",
+			daemonSet: `{"metadata":{"generation":2},"spec":{"template":{"spec":{"containers":[{"image":"` + image + `"}]}}},"status":{"observedGeneration":2,"desiredNumberScheduled":3,"updatedNumberScheduled":3,"numberAvailable":2}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/apis/apps/v1/namespaces/kube-system/daemonsets/kube-proxy" {
+					http.NotFound(w, r)
+					return
+				}
+
+				w.Write([]byte(tc.daemonSet))
+			}))
+			defer srv.Close()
+
+			k8s := &kubernetesClient{endpoint: srv.URL, client: srv.Client()}
+			err := k8s.daemonSetRolledOut(context.Background(), "kube-system", "kube-proxy", image)
+			if rolledOut := err == nil; rolledOut != tc.rolledOut {
+				t.Errorf("expected rolled out %v, got error %v", tc.rolledOut, err)
+			}
+		})
+	}
+}
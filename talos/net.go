@@ -5,6 +5,8 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"net"
+	"strconv"
 	"time"
 
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
@@ -48,7 +50,12 @@ func waitTillTalosMachineUp(ctx context.Context, tlsConfig *tls.Config, host str
 	ctx, cancel := context.WithTimeout(ctx, 180*time.Second)
 	defer cancel()
 
-	for _, err := grpc.Dial(host, opts...); err != nil; {
+	for {
+		conn, err := grpc.DialContext(ctx, host, opts...)
+		if err == nil {
+			return conn.Close()
+		}
+
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
@@ -57,8 +64,30 @@ func waitTillTalosMachineUp(ctx context.Context, tlsConfig *tls.Config, host str
 			time.Sleep(5 * time.Second)
 		}
 	}
+}
+
+// reachTimeout is how long a node is given to accept a connection before it is considered unreachable.
+var reachTimeout = 30 * time.Second
+
+// reachable checks that the Talos API of the node at ip accepts a secure connection.
+func reachable(ctx context.Context, input generate.Input, ip string) error {
+	tlsConfig, err := makeTLSConfig(*input.Certs, true)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reachTimeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, net.JoinHostPort(ip, strconv.Itoa(talosPort)),
+		grpc.WithTransportCredentials(credentials.NewTLS(&tlsConfig)),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to connect to the Talos API within %s: %w", reachTimeout, err)
+	}
 
-	return nil
+	return conn.Close()
 }
 
 func insecureConn(ctx context.Context, host string) (*grpc.ClientConn, error) {
@@ -0,0 +1,221 @@
+package talos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	ghodssyaml "github.com/ghodss/yaml"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/config/configloader"
+	"github.com/talos-systems/talos/pkg/machinery/config/configpatcher"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+	"gopkg.in/yaml.v3"
+)
+
+// configPatchesAttribute is shared by node resources to patch parts of the machine configuration not modelled by
+// the provider's schema.
+var configPatchesAttribute = tfsdk.Attribute{
+	Type:     types.ListType{ElemType: types.StringType},
+	Optional: true,
+	MarkdownDescription: "Patches applied, in order, to the node's generated machine configuration, for settings the resource doesn't " +
+		"model. Patches changing settings configured by the resource's other attributes are refused. Each patch is either a list of " +
+		"RFC 6902 JSON Patch operations, in JSON or YAML, or a YAML fragment of the machine configuration that is merged into it. " +
+		"When merging, maps are merged recursively, lists are appended to and all other values are replaced. Every patched " +
+		"configuration must pass Talos' validation for the node's runtime mode.",
+}
+
+// configPatchError is an error caused by one of a node's config_patches.
+type configPatchError struct {
+	index int
+	err   error
+}
+
+func (e *configPatchError) Error() string {
+	return fmt.Sprintf("config patch %d: %s", e.index, e.err)
+}
+
+func (e *configPatchError) Unwrap() error {
+	return e.err
+}
+
+// configDiagnostics reports an error rendering a node's configuration. Errors caused by a config patch are attributed
+// to it.
+func configDiagnostics(summary string, err error) diag.Diagnostics {
+	return nodeConfigDiagnostics(path.Empty(), summary, err)
+}
+
+// nodeConfigDiagnostics is configDiagnostics for a node nested at node, such as a talos_cluster's node.
+func nodeConfigDiagnostics(node path.Path, summary string, err error) (diags diag.Diagnostics) {
+	var patchErr *configPatchError
+	if errors.As(err, &patchErr) {
+		diags.AddAttributeError(node.AtName("config_patches").AtListIndex(patchErr.index), summary, patchErr.err.Error())
+		return
+	}
+
+	diags.AddError(summary, err.Error())
+	return
+}
+
+// patchConfig applies config patches, in order, to a node's machine configuration. Each patch is applied to the
+// configuration serialised, which is then loaded and validated for mode so that errors can be attributed to the
+// patch that caused them. read returns the node's attributes as read from a configuration. Patches changing them are
+// refused, as the attributes would be read back with the patched values and differ from the plan on every run.
+func patchConfig(cfg *v1alpha1.Config, patches []types.String, mode runtimeMode, read func(*v1alpha1.Config) (any, error)) (*v1alpha1.Config, error) {
+	if len(patches) == 0 {
+		return cfg, nil
+	}
+
+	before, err := read(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, patch := range patches {
+		serialised, err := cfg.Bytes()
+		if err != nil {
+			return nil, err
+		}
+
+		patched, err := applyConfigPatch(serialised, patch.Value)
+		if err != nil {
+			return nil, &configPatchError{index: i, err: err}
+		}
+
+		provider, err := configloader.NewFromBytes(patched)
+		if err != nil {
+			return nil, &configPatchError{index: i, err: fmt.Errorf("patched configuration is invalid: %w", err)}
+		}
+
+		if _, err := provider.Validate(mode, config.WithLocal()); err != nil {
+			return nil, &configPatchError{index: i, err: fmt.Errorf("patched configuration is rejected by Talos in %s mode: %w", mode, err)}
+		}
+
+		patchedCfg, ok := provider.Raw().(*v1alpha1.Config)
+		if !ok {
+			return nil, &configPatchError{index: i, err: fmt.Errorf("patched configuration isn't a v1alpha1 machine configuration")}
+		}
+
+		after, err := read(patchedCfg)
+		if err != nil {
+			return nil, &configPatchError{index: i, err: err}
+		}
+
+		if changed := changedAttributes(reflect.ValueOf(before), reflect.ValueOf(after), ""); len(changed) > 0 {
+			return nil, &configPatchError{index: i, err: fmt.Errorf("the patch changes settings modelled by the resource, "+
+				"configure them through %s instead", strings.Join(changed, ", "))}
+		}
+
+		cfg, before = patchedCfg, after
+	}
+
+	return cfg, nil
+}
+
+// changedAttributes returns the names of the attributes differing between two values of a resource's data.
+// Attributes of embedded structs, such as a node's config, are named after the struct's attribute.
+func changedAttributes(before, after reflect.Value, prefix string) []string {
+	for before.Kind() == reflect.Pointer || before.Kind() == reflect.Interface {
+		before, after = before.Elem(), after.Elem()
+	}
+
+	changed := []string{}
+	for i := 0; i < before.NumField(); i++ {
+		field := before.Type().Field(i)
+		name, ok := field.Tag.Lookup("tfsdk")
+		if !ok {
+			continue
+		}
+
+		if field.Anonymous {
+			changed = append(changed, changedAttributes(before.Field(i), after.Field(i), prefix+name+".")...)
+			continue
+		}
+
+		if !reflect.DeepEqual(before.Field(i).Interface(), after.Field(i).Interface()) {
+			changed = append(changed, prefix+name)
+		}
+	}
+
+	return changed
+}
+
+// applyConfigPatch applies a single patch. Patches which are a list are treated as JSON Patch operations, and
+// patches which are a map as a configuration fragment to merge.
+func applyConfigPatch(cfg []byte, patch string) ([]byte, error) {
+	var doc any
+	if err := yaml.Unmarshal([]byte(patch), &doc); err != nil {
+		return nil, fmt.Errorf("unable to parse patch: %w", err)
+	}
+
+	switch doc.(type) {
+	case []any:
+		ops, err := configpatcher.LoadPatch([]byte(patch))
+		if err != nil {
+			return nil, fmt.Errorf("unable to load JSON patch: %w", err)
+		}
+
+		return configpatcher.JSON6902(cfg, ops)
+	case map[string]any:
+		return strategicMerge(cfg, []byte(patch))
+	default:
+		return nil, fmt.Errorf("patch must be a list of JSON patch operations or a configuration fragment")
+	}
+}
+
+// strategicMerge merges a YAML configuration fragment into a rendered machine configuration.
+func strategicMerge(cfg []byte, fragment []byte) ([]byte, error) {
+	var base, patch map[string]any
+
+	for _, doc := range []struct {
+		in  []byte
+		out *map[string]any
+	}{{cfg, &base}, {fragment, &patch}} {
+		js, err := ghodssyaml.YAMLToJSON(doc.in)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(js, doc.out); err != nil {
+			return nil, err
+		}
+	}
+
+	merged, err := json.Marshal(mergeValue(base, patch))
+	if err != nil {
+		return nil, err
+	}
+
+	return ghodssyaml.JSONToYAML(merged)
+}
+
+func mergeValue(base, patch any) any {
+	switch p := patch.(type) {
+	case map[string]any:
+		b, ok := base.(map[string]any)
+		if !ok {
+			return p
+		}
+
+		for key, value := range p {
+			b[key] = mergeValue(b[key], value)
+		}
+
+		return b
+	case []any:
+		b, ok := base.([]any)
+		if !ok {
+			return p
+		}
+
+		return append(b, p...)
+	default:
+		return p
+	}
+}
@@ -0,0 +1,72 @@
+package talos
+
+import (
+	"terraform-provider-talos/talos/datatypes"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	configloader "github.com/talos-systems/talos/pkg/machinery/config/configloader"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
+)
+
+// TestConfigPatches checks that JSON patches and configuration fragments are applied to a generated configuration.
+func TestConfigPatches(t *testing.T) {
+	data := *talosControlNodeResourceDataExample
+	data.Patches = []types.String{
+		datatypes.Wraps(`[{"op": "add", "path": "/machine/kubelet/extraConfig", "value": {"serverTLSBootstrap": true}}]`),
+		datatypes.Wraps("machine:\n  network:\n    disableSearchDomain: true\n"),
+	}
+
+	confString, err := genConfig(machine.TypeControlPlane, &datatypes.InputBundleExample, &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := configloader.NewFromBytes(confString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := provider.Raw().(*v1alpha1.Config)
+
+	if v := cfg.MachineConfig.MachineKubelet.KubeletExtraConfig.Object["serverTLSBootstrap"]; v != true {
+		t.Errorf("expected JSON patch to set kubelet extra config, got %v", v)
+	}
+
+	if !cfg.MachineConfig.MachineNetwork.NetworkDisableSearchDomain {
+		t.Errorf("expected merge patch to disable the search domain")
+	}
+}
+
+// TestConfigPatchErrors checks that errors, including patches changing settings modelled by the resource and
+// patched configurations Talos rejects, are attributed to the failing patch.
+func TestConfigPatchErrors(t *testing.T) {
+	for _, patch := range []string{
+		`[{"op": "replace", "path": "/machine/doesnotexist", "value": "1"}]`,
+		"machine:\n  notAField: true\n",
+		"just a string",
+		"machine:\n  sysctls:\n    a: \"1\"\n",
+		`[{"op": "add", "path": "/machine/kubelet/extraArgs/max-pods", "value": "250"}]`,
+		"machine:\n  network:\n    interfaces:\n      - interface: eth0\n        mtu: 9000\n",
+		`[{"op": "replace", "path": "/cluster/network/dnsDomain", "value": ""}]`,
+	} {
+		data := *talosControlNodeResourceDataExample
+		data.Patches = []types.String{
+			datatypes.Wraps("machine:\n  network:\n    disableSearchDomain: true\n"),
+			datatypes.Wraps(patch),
+		}
+
+		_, err := genConfig(machine.TypeControlPlane, &datatypes.InputBundleExample, &data)
+		if err == nil {
+			t.Errorf("expected patch %q to fail", patch)
+			continue
+		}
+
+		diags := configDiagnostics("Unable to generate talos node config.", err)
+		if expected := path.Root("config_patches").AtListIndex(1); len(diags) != 1 || !diags[0].(diag.DiagnosticWithPath).Path().Equal(expected) {
+			t.Errorf("expected patch %q to be reported on %s, got %v", patch, expected, diags)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package talos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"terraform-provider-talos/talos/datatypes"
+
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	onDestroyReset        = "reset"
+	onDestroyLeaveRunning = "leave_running"
+	onDestroyFail         = "fail"
+
+	onUnreachableError           = "error"
+	onUnreachableKeepState       = "keep_state_with_warning"
+	onUnreachableRemoveFromState = "remove_from_state"
+)
+
+var (
+	onDestroyPolicies     = []string{onDestroyReset, onDestroyLeaveRunning, onDestroyFail}
+	onUnreachablePolicies = []string{onUnreachableError, onUnreachableKeepState, onUnreachableRemoveFromState}
+)
+
+// onDestroyAttribute is shared by node resources to choose what happens to a node when its resource is destroyed.
+var onDestroyAttribute = tfsdk.Attribute{
+	Type:     types.StringType,
+	Optional: true,
+	MarkdownDescription: "What happens to the node when the resource is destroyed. `reset` removes the node from the cluster and resets it, " +
+		"`leave_running` only removes the resource from state and `fail` refuses to destroy the resource. Defaults to `reset`.",
+	Validators: []tfsdk.AttributeValidator{
+		datatypes.ValidateOneOf(onDestroyPolicies...),
+	},
+}
+
+// onUnreachableAttribute is shared by node resources to choose how a refresh treats a node which can't be reached.
+var onUnreachableAttribute = tfsdk.Attribute{
+	Type:     types.StringType,
+	Optional: true,
+	MarkdownDescription: "How a refresh handles the node's Talos API being unreachable. `error` fails the refresh, `keep_state_with_warning` " +
+		"keeps the last known state and warns, and `remove_from_state` forgets the node so it is created again. Defaults to `error`.",
+	Validators: []tfsdk.AttributeValidator{
+		datatypes.ValidateOneOf(onUnreachablePolicies...),
+	},
+}
+
+// policy returns the value of a policy attribute, or def if it is unset, and checks it is one of allowed.
+func policy(value types.String, def string, allowed []string) (string, error) {
+	if value.Null || value.Unknown || value.Value == "" {
+		return def, nil
+	}
+
+	for _, p := range allowed {
+		if value.Value == p {
+			return p, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown policy %q, expected one of %s", value.Value, strings.Join(allowed, ", "))
+}
+
+// resetOnDestroy applies a resource's on_destroy policy. It returns whether the node should be removed from the
+// cluster and reset, and records an error when destroying the resource is refused.
+func resetOnDestroy(value types.String, diags *diag.Diagnostics) bool {
+	p, err := policy(value, onDestroyReset, onDestroyPolicies)
+	if err != nil {
+		diags.AddError("Invalid on_destroy policy.", err.Error())
+		return false
+	}
+
+	switch p {
+	case onDestroyFail:
+		diags.AddError("Destroying the node is not allowed.",
+			"The resource's on_destroy policy is fail. Change it to reset or leave_running to destroy the resource.")
+		return false
+	case onDestroyLeaveRunning:
+		return false
+	default:
+		return true
+	}
+}
+
+// unreachable checks that the node at ip can be reached before it is refreshed. If it can't, the resource's
+// on_unreachable policy is applied to resp and true is returned, the refresh should then stop.
+func unreachable(ctx context.Context, value types.String, input generate.Input, ip string, resp *tfsdk.ReadResourceResponse) bool {
+	p, err := policy(value, onUnreachableError, onUnreachablePolicies)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid on_unreachable policy.", err.Error())
+		return true
+	}
+
+	err = reachable(ctx, input, ip)
+	if err == nil {
+		return false
+	}
+
+	switch p {
+	case onUnreachableKeepState:
+		resp.Diagnostics.AddWarning("Node "+ip+" is unreachable, keeping its last known state.", err.Error())
+	case onUnreachableRemoveFromState:
+		resp.Diagnostics.AddWarning("Node "+ip+" is unreachable, removing it from state.", err.Error())
+		resp.State.RemoveResource(ctx)
+	default:
+		resp.Diagnostics.AddError("Node "+ip+" is unreachable.",
+			err.Error()+"\n\nSet on_unreachable to keep_state_with_warning or remove_from_state to refresh without the node.")
+	}
+
+	return true
+}
@@ -0,0 +1,77 @@
+package talos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestResetOnDestroy(t *testing.T) {
+	for _, tc := range []struct {
+		value types.String
+		reset bool
+		err   bool
+	}{
+		{value: types.String{Null: true}, reset: true},
+		{value: types.String{Value: "reset"}, reset: true},
+		{value: types.String{Value: "leave_running"}, reset: false},
+		{value: types.String{Value: "fail"}, reset: false, err: true},
+		{value: types.String{Value: "explode"}, reset: false, err: true},
+	} {
+		var diags diag.Diagnostics
+		if reset := resetOnDestroy(tc.value, &diags); reset != tc.reset || diags.HasError() != tc.err {
+			t.Errorf("on_destroy %q: expected reset %v and error %v, got %v and %v", tc.value.Value, tc.reset, tc.err, reset, diags)
+		}
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	p, err := policy(types.String{Null: true}, onUnreachableError, onUnreachablePolicies)
+	if err != nil || p != onUnreachableError {
+		t.Errorf("expected the default policy, got %q, %v", p, err)
+	}
+
+	p, err = policy(types.String{Value: "remove_from_state"}, onUnreachableError, onUnreachablePolicies)
+	if err != nil || p != onUnreachableRemoveFromState {
+		t.Errorf("expected remove_from_state, got %q, %v", p, err)
+	}
+
+	if _, err = policy(types.String{Value: "ignore"}, onUnreachableError, onUnreachablePolicies); err == nil {
+		t.Errorf("expected an unknown policy to fail")
+	}
+}
+
+// TestPolicyAttributes checks that misspelt policies are rejected when the configuration is validated.
+func TestPolicyAttributes(t *testing.T) {
+	for name, tc := range map[string]struct {
+		attribute tfsdk.Attribute
+		valid     []string
+		invalid   []string
+	}{
+		"on_destroy":     {onDestroyAttribute, onDestroyPolicies, []string{"Reset", "leave-running"}},
+		"on_unreachable": {onUnreachableAttribute, onUnreachablePolicies, []string{"keep_state", "remove"}},
+	} {
+		for _, values := range []struct {
+			values []string
+			valid  bool
+		}{{tc.valid, true}, {tc.invalid, false}} {
+			for _, value := range values.values {
+				resp := &tfsdk.ValidateAttributeResponse{}
+				for _, v := range tc.attribute.Validators {
+					v.Validate(context.Background(), tfsdk.ValidateAttributeRequest{
+						AttributePath:   path.Root(name),
+						AttributeConfig: types.String{Value: value},
+					}, resp)
+				}
+
+				if resp.Diagnostics.HasError() == values.valid {
+					t.Errorf("%s %q: expected valid %v, got %v", name, value, values.valid, resp.Diagnostics)
+				}
+			}
+		}
+	}
+}
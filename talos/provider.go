@@ -3,8 +3,7 @@ package talos
 import (
 	"context"
 	"fmt"
-	"os"
-	"strconv"
+	"sync"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
@@ -19,13 +18,9 @@ var _ tfsdk.Provider = &provider{}
 
 type provider struct {
 	configured bool
-	// skipdelete and skipread are used to recover from broken deployments, where the node fails to deploy,
-	// but the provider thinks it exists. In this situation it will try to refresh its status using talos's api,
-	// which will not be up because nodes are in a broken state. This will cause the plugin to hang and timeout when
-	// connecting.
-	skipdelete bool
-	skipread   bool
 	version    string
+	// etcdLock serializes the operations of the provider's resources which remove or reboot an etcd member.
+	etcdLock *sync.Mutex
 }
 
 // Configure creates an instance of a Talos API helper struct and set it as the "client" attribute for the provider struct.
@@ -34,35 +29,19 @@ func (p *provider) Configure(ctx context.Context, req tfsdk.ConfigureProviderReq
 		return
 	}
 
-	if val, set := os.LookupEnv("TALOS_SKIPDELETE"); set {
-		b, err := strconv.ParseBool(val)
-		if err != nil {
-			resp.Diagnostics.AddError("environment parse error",
-				"error parsing boolean value for TALOS_SKIPDELETE")
-		}
-
-		p.skipdelete = b
-	}
-
-	if val, set := os.LookupEnv("TALOS_SKIPREAD"); set {
-		b, err := strconv.ParseBool(val)
-		if err != nil {
-			resp.Diagnostics.AddError("environment parse error",
-				"error parsing boolean value for TALOS_SKIPREAD")
-		}
-
-		p.skipread = b
-	}
-
 	p.configured = true
 }
 
 // GetResources returns a map of all provider resources.
 func (p *provider) GetResources(ctx context.Context) (map[string]tfsdk.ResourceType, diag.Diagnostics) {
 	return map[string]tfsdk.ResourceType{
-		"talos_configuration": talosClusterConfigResourceType{},
-		"talos_control_node":  talosControlNodeResourceType{},
-		"talos_worker_node":   talosWorkerNodeResourceType{},
+		"talos_configuration":               talosClusterConfigResourceType{},
+		"talos_control_node":                talosControlNodeResourceType{},
+		"talos_worker_node":                 talosWorkerNodeResourceType{},
+		"talos_kubernetes_upgrade":          talosKubernetesUpgradeResourceType{},
+		"talos_cluster":                     talosClusterResourceType{},
+		"talos_machine_configuration_apply": talosMachineConfigurationApplyResourceType{},
+		"talos_wireguard_mesh":              talosWireguardMeshResourceType{},
 	}, nil
 }
 
@@ -87,7 +66,8 @@ func (p *provider) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostic
 func New(version string) func() tfsdk.Provider {
 	return func() tfsdk.Provider {
 		return &provider{
-			version: version,
+			version:  version,
+			etcdLock: &sync.Mutex{},
 		}
 	}
 }
@@ -1,6 +1,7 @@
 package talos
 
 import (
+	"context"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
@@ -20,3 +21,21 @@ func testAccPreCheck(t *testing.T) {
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
 }
+
+// TestBaseConfigSensitive checks that every resource taking the base configuration, which holds the cluster's secrets,
+// marks it sensitive.
+func TestBaseConfigSensitive(t *testing.T) {
+	p := New("test")().(*provider)
+	resources, _ := p.GetResources(context.Background())
+
+	for name, resourceType := range resources {
+		schema, diags := resourceType.GetSchema(context.Background())
+		if diags.HasError() {
+			t.Fatalf("%s: %v", name, diags)
+		}
+
+		if attribute, ok := schema.Attributes["base_config"]; ok && !attribute.Sensitive {
+			t.Errorf("expected the base_config of %s to be sensitive", name)
+		}
+	}
+}
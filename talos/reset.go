@@ -0,0 +1,278 @@
+package talos
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/api/storage"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	// maintenanceTimeout is how long a reset node is given to come back up in maintenance mode.
+	maintenanceTimeout = 10 * time.Minute
+	// resetPartitions are the system partitions which may be wiped individually when resetting a node.
+	resetPartitions = []string{"STATE", "EPHEMERAL"}
+)
+
+// resetAttribute is shared by node resources to configure how a node is reset when it is destroyed.
+var resetAttribute = tfsdk.Attribute{
+	Optional: true,
+	MarkdownDescription: "How the node is reset when the resource is destroyed. Without this block the node is forcefully reset, " +
+		"all system partitions are wiped and the node is rebooted.",
+	Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+		"graceful": {
+			Type:     types.BoolType,
+			Optional: true,
+			MarkdownDescription: "Whether the node should cordon and drain itself and leave etcd before resetting. A control node reset " +
+				"forcefully has its etcd member removed by the provider instead. Defaults to `false`.",
+		},
+		"wipe": {
+			Type:                types.ListType{ElemType: types.StringType},
+			Optional:            true,
+			MarkdownDescription: "System partitions to wipe, any of `STATE` and `EPHEMERAL`. All system partitions are wiped if unset.",
+		},
+		"reboot": {
+			Type:                types.BoolType,
+			Optional:            true,
+			MarkdownDescription: "Whether the node reboots after resetting. The node is shut down if `false`. Defaults to `true`.",
+		},
+		"wait": {
+			Type:     types.BoolType,
+			Optional: true,
+			MarkdownDescription: "Whether to wait until the node is back in maintenance mode, so it can be provisioned again straight away. " +
+				"Requires `reboot`. Defaults to `false`.",
+		},
+	}),
+}
+
+// resetOptions describes how a node is reset.
+type resetOptions struct {
+	Graceful types.Bool     `tfsdk:"graceful"`
+	Wipe     []types.String `tfsdk:"wipe"`
+	Reboot   types.Bool     `tfsdk:"reboot"`
+	Wait     types.Bool     `tfsdk:"wait"`
+}
+
+// validate checks the options of the reset block at p. Values which aren't known yet are checked once they are.
+func (opts *resetOptions) validate(p path.Path) (diags diag.Diagnostics) {
+	if opts == nil {
+		return
+	}
+
+	if opts.Wait.Value && !opts.Reboot.Null && !opts.Reboot.Unknown && !opts.Reboot.Value {
+		diags.AddAttributeError(p.AtName("wait"), "Invalid reset options.",
+			"A node can't be waited on to return to maintenance mode if it doesn't reboot.")
+	}
+
+	for i, label := range opts.Wipe {
+		if label.Null || label.Unknown {
+			continue
+		}
+
+		valid := false
+		for _, partition := range resetPartitions {
+			valid = valid || label.Value == partition
+		}
+
+		if !valid {
+			diags.AddAttributeError(p.AtName("wipe").AtListIndex(i), "Invalid reset options.",
+				fmt.Sprintf("Unable to wipe partition %q, expected one of %s.", label.Value, strings.Join(resetPartitions, ", ")))
+		}
+	}
+
+	return
+}
+
+// request builds the Talos reset request for the options. Nil options reset the node forcefully, wiping every
+// system partition and rebooting.
+func (opts *resetOptions) request() (*machine.ResetRequest, error) {
+	req := &machine.ResetRequest{
+		Graceful: false,
+		Reboot:   true,
+	}
+
+	if opts == nil {
+		return req, nil
+	}
+
+	if diags := opts.validate(path.Root("reset")); diags.HasError() {
+		return nil, fmt.Errorf("%s", diags.Errors()[0].Detail())
+	}
+
+	if !opts.Graceful.Null {
+		req.Graceful = opts.Graceful.Value
+	}
+
+	if !opts.Reboot.Null {
+		req.Reboot = opts.Reboot.Value
+	}
+
+	for _, label := range opts.Wipe {
+		req.SystemPartitionsToWipe = append(req.SystemPartitionsToWipe, &machine.ResetPartitionSpec{
+			Label: label.Value,
+			Wipe:  true,
+		})
+	}
+
+	return req, nil
+}
+
+// resetNode resets a node as described by opts. If requested, it then waits for the node to come back up in maintenance
+// mode at maintenanceIP.
+func resetNode(ctx context.Context, input generate.Input, ip string, opts *resetOptions, maintenanceIP string) error {
+	req, err := opts.request()
+	if err != nil {
+		return err
+	}
+
+	conn, err := secureConn(ctx, input, net.JoinHostPort(ip, strconv.Itoa(talosPort)))
+	if err != nil {
+		return fmt.Errorf("error while attempting to connect to Talos API endpoint: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err = machine.NewMachineServiceClient(conn).Reset(ctx, req); err != nil {
+		return err
+	}
+
+	if opts == nil || !opts.Wait.Value {
+		return nil
+	}
+
+	return waitMaintenance(ctx, net.JoinHostPort(maintenanceIP, strconv.Itoa(talosPort)))
+}
+
+// decommissionNode takes a node out of the cluster and resets it as described by opts. The node is cordoned and
+// drained, reset and then has its Kubernetes Node object deleted, as a kubelet which is still running would
+// register the Node again. A control plane node reset forcefully first leaves etcd, or has its member removed through
+// one of peers if it is unreachable. A graceful reset makes Talos leave etcd itself, so the member isn't removed
+// explicitly then. A Kubernetes API which can't be reached is reported as a warning, as the node can still be reset
+// without it.
+func decommissionNode(ctx context.Context, input generate.Input, ip string, name string, control bool, peers []string,
+	opts *resetOptions, maintenanceIP string) (diags diag.Diagnostics) {
+	graceful := opts != nil && opts.Graceful.Value
+	if control && !graceful {
+		if err := leaveEtcd(ctx, input, ip, name, peers); err != nil {
+			diags.AddError("Unable to remove the node from etcd.", err.Error())
+			return
+		}
+	}
+
+	k8s, err := newKubernetesClient(input)
+	if err != nil {
+		diags.AddError("Unable to remove the node from Kubernetes.", err.Error())
+		return
+	}
+
+	unreachable := func(err error) {
+		diags.AddWarning("Unable to remove node "+name+" from Kubernetes, the Kubernetes API is unreachable.",
+			err.Error()+"\n\nThe node is reset regardless, its Node object has to be deleted once the API is reachable.")
+	}
+
+	err = k8s.drainNode(ctx, name)
+	reachable := !apiUnreachable(err)
+	if !reachable {
+		unreachable(err)
+	} else if err != nil {
+		diags.AddError("Unable to remove the node from Kubernetes.", err.Error())
+		return
+	}
+
+	if err := resetNode(ctx, input, ip, opts, maintenanceIP); err != nil {
+		diags.AddError("Unable to reset the node.", err.Error())
+		return
+	}
+
+	if !reachable {
+		return
+	}
+
+	if err := k8s.deleteNode(ctx, name); apiUnreachable(err) {
+		unreachable(err)
+	} else if err != nil {
+		diags.AddError("Unable to remove the node from Kubernetes.", err.Error())
+	}
+
+	return
+}
+
+// controlPlanePeers returns the addresses a control node's etcd membership can be read and managed through when the
+// node at ip can't be reached: the other control plane nodes registered in Kubernetes, followed by the host of the
+// control plane endpoint as a last resort. The endpoint is often a load balancer which only serves the Kubernetes
+// API, or a VIP held by the node itself, so it is tried last.
+func controlPlanePeers(ctx context.Context, input generate.Input, ip string) []string {
+	peers := []string{}
+
+	k8s, err := newKubernetesClient(input)
+	if err == nil {
+		var addresses []string
+		if addresses, err = k8s.controlPlaneAddresses(ctx); err == nil {
+			for _, address := range addresses {
+				if address != ip {
+					peers = append(peers, address)
+				}
+			}
+		}
+	}
+	if err != nil {
+		tflog.Warn(ctx, "Unable to list the control plane nodes in Kubernetes. Reason "+err.Error())
+	}
+
+	if endpoint, err := url.Parse(input.GetControlPlaneEndpoint()); err == nil && endpoint.Hostname() != "" && endpoint.Hostname() != ip {
+		peers = append(peers, endpoint.Hostname())
+	}
+
+	return peers
+}
+
+// waitMaintenance waits until the node at host serves the maintenance API. Only nodes in maintenance mode answer
+// requests from clients without a certificate.
+func waitMaintenance(ctx context.Context, host string) error {
+	tlsConfig, err := makeTLSConfig(generate.Certs{}, false)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, maintenanceTimeout)
+	defer cancel()
+
+	for {
+		err := func() error {
+			conn, err := grpc.DialContext(ctx, host, grpc.WithTransportCredentials(credentials.NewTLS(&tlsConfig)))
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			_, err = storage.NewStorageServiceClient(conn).Disks(ctx, &emptypb.Empty{})
+			return err
+		}()
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for the node to enter maintenance mode: %w", err)
+		default:
+			tflog.Info(ctx, "Waiting for "+host+" to enter maintenance mode, reason "+err.Error())
+			time.Sleep(componentPollInterval)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package talos
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// TestResetRequest checks that a node's reset block is translated into a Talos reset request.
+func TestResetRequest(t *testing.T) {
+	var opts *resetOptions
+	req, err := opts.request()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Graceful || !req.Reboot || len(req.SystemPartitionsToWipe) != 0 {
+		t.Errorf("expected a forced reset wiping every partition by default, got %v", req)
+	}
+
+	opts = &resetOptions{
+		Graceful: types.Bool{Value: true},
+		Wipe:     []types.String{{Value: "STATE"}, {Value: "EPHEMERAL"}},
+		Reboot:   types.Bool{Null: true},
+		Wait:     types.Bool{Value: true},
+	}
+	req, err = opts.request()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !req.Graceful || !req.Reboot || len(req.SystemPartitionsToWipe) != 2 || req.SystemPartitionsToWipe[1].Label != "EPHEMERAL" {
+		t.Errorf("unexpected reset request %v", req)
+	}
+
+	for _, opts := range []*resetOptions{
+		{Wipe: []types.String{{Value: "BOOT"}}},
+		{Reboot: types.Bool{Value: false}, Wait: types.Bool{Value: true}},
+	} {
+		if _, err := opts.request(); err == nil {
+			t.Errorf("expected invalid reset options %v to fail", opts)
+		}
+	}
+}
+
+// TestResetValidate checks that invalid reset options are reported on the attribute causing them, and that values
+// which aren't known yet are accepted.
+func TestResetValidate(t *testing.T) {
+	base := path.Root("control_nodes").AtListIndex(1).AtName("reset")
+	for _, tc := range []struct {
+		opts     *resetOptions
+		expected []path.Path
+	}{
+		{nil, nil},
+		{&resetOptions{Wipe: []types.String{{Value: "STATE"}, {Value: "BOOT"}}}, []path.Path{base.AtName("wipe").AtListIndex(1)}},
+		{&resetOptions{Reboot: types.Bool{Value: false}, Wait: types.Bool{Value: true}}, []path.Path{base.AtName("wait")}},
+		{&resetOptions{Reboot: types.Bool{Unknown: true}, Wait: types.Bool{Value: true}, Wipe: []types.String{{Unknown: true}}}, nil},
+	} {
+		diags := tc.opts.validate(base)
+		if len(diags) != len(tc.expected) {
+			t.Errorf("expected %d errors for %v, got %v", len(tc.expected), tc.opts, diags)
+			continue
+		}
+
+		for i, expected := range tc.expected {
+			if p := diags[i].(diag.DiagnosticWithPath).Path(); !p.Equal(expected) {
+				t.Errorf("expected an error on %s, got %s", expected, p)
+			}
+		}
+	}
+}
@@ -0,0 +1,933 @@
+package talos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"terraform-provider-talos/talos/datatypes"
+
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+	machinetype "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ tfsdk.ResourceType = talosClusterResourceType{}
+var _ tfsdk.Resource = talosClusterResource{}
+var _ tfsdk.ResourceWithModifyPlan = talosClusterResource{}
+var _ tfsdk.ResourceWithValidateConfig = talosClusterResource{}
+
+var (
+	// nodeHealthTimeout is how long a batch of nodes is given to pass the health gate after a change is applied.
+	nodeHealthTimeout = 15 * time.Minute
+	// rebootGracePeriod is how long to wait after applying a configuration before polling the node's health,
+	// giving the node time to begin rebooting so a stale healthy status isn't observed.
+	rebootGracePeriod = 30 * time.Second
+)
+
+type talosClusterResourceType struct{}
+
+func clusterNodeSchema(description string) tfsdk.Attribute {
+	return tfsdk.Attribute{
+		Description: description,
+		Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+			"name": {
+				Type:        types.StringType,
+				Required:    true,
+				Description: "The node's name. Used to identify the node across rollouts and as its Kubernetes node name if no hostname is configured.",
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateDomain(),
+				},
+			},
+			"provision_ip": {
+				Type:        types.StringType,
+				Required:    true,
+				Description: "IP address of the machine to be provisioned.",
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateIP(),
+				},
+			},
+			"configure_ip": {
+				Type:        types.StringType,
+				Required:    true,
+				Description: "IP address used to access the Talos API once the node is configured.",
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateIP(),
+				},
+			},
+			"config": {
+				Required:    true,
+				Description: datatypes.TalosConfigSchema.MarkdownDescription,
+				Attributes:  tfsdk.SingleNestedAttributes(datatypes.TalosConfigSchema.Attributes),
+			},
+			"config_patches": configPatchesAttribute,
+			"reset":          resetAttribute,
+			"apply_mode":     applyModeAttributes["apply_mode"],
+			"try_timeout":    applyModeAttributes["try_timeout"],
+		}),
+	}
+}
+
+func (t talosClusterResourceType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	controlNodes := clusterNodeSchema("Control plane nodes. Changes are rolled out to these before any worker node, and the first node is used to bootstrap etcd.")
+	controlNodes.Required = true
+
+	workerNodes := clusterNodeSchema("Worker nodes.")
+	workerNodes.Optional = true
+
+	return tfsdk.Schema{
+		MarkdownDescription: "Represents a set of Talos nodes whose configuration changes are rolled out as a rolling update, " +
+			"limited by a disruption budget and gated on the health of etcd and Kubernetes between batches.",
+		Attributes: map[string]tfsdk.Attribute{
+			"control_nodes": controlNodes,
+			"worker_nodes":  workerNodes,
+			"max_unavailable": {
+				Type:        types.Int64Type,
+				Optional:    true,
+				Description: "Maximum number of nodes changed at once. Defaults to 1. Control plane batches are further limited so that etcd quorum is never lost.",
+			},
+			"on_destroy": onDestroyAttribute,
+			"base_config": {
+				Type:      types.StringType,
+				Required:  true,
+				Sensitive: true,
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateBaseConfig(),
+				},
+			},
+			"bootstrapped": {
+				Type:        types.BoolType,
+				Computed:    true,
+				Description: "Whether etcd has been bootstrapped on the first control plane node.",
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					tfsdk.UseStateForUnknown(),
+				},
+			},
+			"id": {
+				Computed:            true,
+				MarkdownDescription: "Identifier, derived from the first control plane node's name.",
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					tfsdk.UseStateForUnknown(),
+				},
+				Type: types.StringType,
+			},
+		},
+	}, nil
+}
+
+type talosClusterNode struct {
+	Name        types.String `tfsdk:"name"`
+	ProvisionIP types.String `tfsdk:"provision_ip"`
+	ConfigIP    types.String `tfsdk:"configure_ip"`
+
+	datatypes.TalosConfig `tfsdk:"config"`
+
+	Patches    []types.String `tfsdk:"config_patches"`
+	Reset      *resetOptions  `tfsdk:"reset"`
+	ApplyMode  types.String   `tfsdk:"apply_mode"`
+	TryTimeout types.String   `tfsdk:"try_timeout"`
+}
+
+type talosClusterResourceData struct {
+	ControlNodes   []talosClusterNode `tfsdk:"control_nodes"`
+	WorkerNodes    []talosClusterNode `tfsdk:"worker_nodes"`
+	MaxUnavailable types.Int64        `tfsdk:"max_unavailable"`
+	OnDestroy      types.String       `tfsdk:"on_destroy"`
+	BaseConfig     types.String       `tfsdk:"base_config"`
+	Bootstrapped   types.Bool         `tfsdk:"bootstrapped"`
+	ID             types.String       `tfsdk:"id"`
+}
+
+// nodeData wraps a cluster node so the control node resource's rendering logic can be reused for it.
+func (n *talosClusterNode) nodeData(baseConfig types.String) *talosControlNodeResourceData {
+	return &talosControlNodeResourceData{
+		Name:        n.Name,
+		TalosConfig: n.TalosConfig,
+		ProvisionIP: n.ProvisionIP,
+		ConfigIP:    n.ConfigIP,
+		Patches:     n.Patches,
+		BaseConfig:  baseConfig,
+	}
+}
+
+// generate fills in the node's derived values which aren't configured, reusing those of prior, the node of the same
+// name in the cluster's state, if there is one. Control nodes derive every value a talos_control_node does, workers
+// only their Wireguard keys, as they're rendered without the control plane's settings.
+func (n *talosClusterNode) generate(baseConfig types.String, prior *talosClusterNode, control bool) error {
+	if !control {
+		if n.Network == nil {
+			return nil
+		}
+
+		var devices []datatypes.NetworkDevice
+		if prior != nil && prior.Network != nil {
+			devices = prior.Network.Devices
+		}
+
+		return generateWireguardKeys(n.Network.Devices, devices)
+	}
+
+	var priorData *talosControlNodeResourceData
+	if prior != nil {
+		priorData = prior.nodeData(baseConfig)
+	}
+
+	data := n.nodeData(baseConfig)
+	if err := data.generate(priorData); err != nil {
+		return err
+	}
+	n.TalosConfig = data.TalosConfig
+
+	return nil
+}
+
+// generate fills in the derived values of the plan's nodes. Nodes are matched to those of prior, the cluster's state,
+// by name rather than by position, so removing or reordering nodes doesn't move one node's values onto another.
+func (plan *talosClusterResourceData) generate(prior *talosClusterResourceData) (diags diag.Diagnostics) {
+	priorNodes := map[string]*talosClusterNode{}
+	if prior != nil {
+		for _, nodes := range [][]talosClusterNode{prior.ControlNodes, prior.WorkerNodes} {
+			for i := range nodes {
+				priorNodes[nodes[i].Name.Value] = &nodes[i]
+			}
+		}
+	}
+
+	for _, role := range []struct {
+		name    string
+		nodes   []talosClusterNode
+		control bool
+	}{{"control_nodes", plan.ControlNodes, true}, {"worker_nodes", plan.WorkerNodes, false}} {
+		for i := range role.nodes {
+			node := &role.nodes[i]
+			if err := node.generate(plan.BaseConfig, priorNodes[node.Name.Value], role.control); err != nil {
+				diags.AddAttributeError(path.Root(role.name).AtListIndex(i), "Unable to generate the node's derived configuration values.", err.Error())
+			}
+		}
+	}
+
+	return
+}
+
+// kubernetesName is the name the node registers itself with in Kubernetes.
+func (n *talosClusterNode) kubernetesName() string {
+	return kubernetesNodeName(n.Network, n.Name)
+}
+
+// clusterRollout holds what is needed to change the configuration of the cluster's nodes.
+type clusterRollout struct {
+	input        generate.Input
+	baseConfig   types.String
+	controlNodes []talosClusterNode
+}
+
+// track adds a control node to those checked by the health gate, replacing the entry of a node of the same name.
+// Nodes are only tracked once they've been applied, so the gate doesn't wait on members which don't exist yet.
+func (r *clusterRollout) track(node talosClusterNode) {
+	for i := range r.controlNodes {
+		if r.controlNodes[i].Name.Value == node.Name.Value {
+			r.controlNodes[i] = node
+			return
+		}
+	}
+
+	r.controlNodes = append(r.controlNodes, node)
+}
+
+// untrack removes a control node from those checked by the health gate.
+func (r *clusterRollout) untrack(name string) {
+	nodes := []talosClusterNode{}
+	for _, node := range r.controlNodes {
+		if node.Name.Value != name {
+			nodes = append(nodes, node)
+		}
+	}
+
+	r.controlNodes = nodes
+}
+
+// peers returns the addresses of the tracked control nodes other than node.
+func (r *clusterRollout) peers(node *talosClusterNode) []string {
+	peers := []string{}
+	for _, control := range r.controlNodes {
+		if control.Name.Value != node.Name.Value {
+			peers = append(peers, control.ConfigIP.Value)
+		}
+	}
+
+	return peers
+}
+
+// remove takes a node out of the cluster and resets it as configured by its reset block. A control node leaves etcd,
+// or has its member removed through one of peers, while the provider's etcd lock is held. If guarded is set, a control
+// node is only removed if etcd keeps its quorum without it.
+func (r *clusterRollout) remove(ctx context.Context, p provider, node talosClusterNode, control, guarded bool, peers []string) (diags diag.Diagnostics) {
+	if control {
+		defer p.lockEtcd(ctx)()
+
+		if guarded {
+			if err := checkQuorum(ctx, r.input, node.ConfigIP.Value, node.kubernetesName(), peers, true); err != nil {
+				diags.AddError("Removing control node "+node.Name.Value+" would break etcd quorum.", err.Error())
+				return
+			}
+		}
+	}
+
+	diags.Append(decommissionNode(ctx, r.input, node.ConfigIP.Value, node.kubernetesName(), control, peers, node.Reset, node.ProvisionIP.Value)...)
+	if diags.HasError() {
+		return
+	}
+
+	if control {
+		r.untrack(node.Name.Value)
+	}
+
+	return
+}
+
+// read refreshes the node's configuration from the one applied to it. Nodes which can't be reached keep their last
+// known configuration.
+func (n *talosClusterNode) read(ctx context.Context, input generate.Input, baseConfig types.String, machineType machinetype.Type) (diags diag.Diagnostics) {
+	if err := reachable(ctx, input, n.ConfigIP.Value); err != nil {
+		diags.AddWarning("Node "+n.Name.Value+" is unreachable, keeping its last known state.", err.Error())
+		return
+	}
+
+	data := n.nodeData(baseConfig)
+	conf, errDesc, err := readConfig(ctx, data, readData{
+		ConfigIP:   n.ConfigIP.Value,
+		BaseConfig: baseConfig.Value,
+	})
+	if err != nil {
+		diags.AddError(errDesc, err.Error())
+		return
+	}
+
+	if err := readNormalized(data, machineType, &input, conf); err != nil {
+		diags.AddError("Error reading the Talos configuration of node "+n.Name.Value+".", err.Error())
+		return
+	}
+	n.TalosConfig = data.TalosConfig
+
+	return
+}
+
+// batchSize returns how many nodes of a role may be changed at once. Control plane batches never exceed the
+// number of members etcd can lose while keeping quorum.
+func batchSize(maxUnavailable types.Int64, nodes int, control bool) int {
+	size := 1
+	if !maxUnavailable.Null && !maxUnavailable.Unknown && maxUnavailable.Value > 0 {
+		size = int(maxUnavailable.Value)
+	}
+
+	if control {
+		tolerance := (nodes - 1) / 2
+		if tolerance < 1 {
+			tolerance = 1
+		}
+		if size > tolerance {
+			size = tolerance
+		}
+	}
+
+	return size
+}
+
+// render generates the node's Talos configuration.
+func (r *clusterRollout) render(node *talosClusterNode, machineType machinetype.Type, generateValues bool) ([]byte, error) {
+	data := node.nodeData(r.baseConfig)
+	if generateValues && machineType == machinetype.TypeControlPlane {
+		if err := data.Generate(); err != nil {
+			return nil, fmt.Errorf("unable to generate initial plan configuration values: %w", err)
+		}
+		node.TalosConfig = data.TalosConfig
+	}
+
+	return genConfig(machineType, &r.input, data)
+}
+
+// provision applies a node's initial configuration through the maintenance API, in the node's apply mode. The node
+// installs Talos and reboots.
+func (r *clusterRollout) provision(ctx context.Context, node *talosClusterNode, machineType machinetype.Type) error {
+	yaml, err := r.render(node, machineType, true)
+	if err != nil {
+		return err
+	}
+
+	req, err := applyRequest(yaml, node.ApplyMode, node.TryTimeout, machine.ApplyConfigurationRequest_REBOOT)
+	if err != nil {
+		return err
+	}
+
+	if warning := maintenanceRequest(req); warning != "" {
+		tflog.Warn(ctx, "Provisioning "+node.Name.Value+": "+warning)
+	}
+
+	conn, err := insecureConn(ctx, net.JoinHostPort(node.ProvisionIP.Value, strconv.Itoa(talosPort)))
+	if err != nil {
+		return fmt.Errorf("unable to make insecure connection to Talos machine: %w", err)
+	}
+
+	_, err = applyConfigRequest(ctx, conn, req)
+	return err
+}
+
+// reconfigure applies a node's configuration through the secure API, in the node's apply mode, if it differs from
+// its previous configuration. It returns whether a change was applied and whether it rebooted the node. A control
+// node's reboot is checked against etcd quorum and holds the provider's etcd lock until its member is healthy again.
+func (r *clusterRollout) reconfigure(ctx context.Context, p provider, prior, node *talosClusterNode, machineType machinetype.Type) (applied, rebooted bool, err error) {
+	before, err := r.render(prior, machineType, false)
+	if err != nil {
+		return false, false, err
+	}
+
+	after, err := r.render(node, machineType, false)
+	if err != nil {
+		return false, false, err
+	}
+
+	if string(before) == string(after) {
+		return false, false, nil
+	}
+
+	req, err := applyRequest(after, node.ApplyMode, node.TryTimeout, machine.ApplyConfigurationRequest_AUTO)
+	if err != nil {
+		return false, false, err
+	}
+
+	ip := node.ConfigIP.Value
+	guarded, restarted := false, time.Time{}
+	if machineType == machinetype.TypeControlPlane {
+		reboots, err := rebootsNode(ctx, r.input, ip, req)
+		if err != nil {
+			return false, false, fmt.Errorf("unable to determine whether the change reboots the node: %w", err)
+		}
+
+		if reboots {
+			defer p.lockEtcd(ctx)()
+
+			if err := checkQuorum(ctx, r.input, ip, node.kubernetesName(), r.peers(node), false); err != nil {
+				return false, false, fmt.Errorf("rebooting the node would break etcd quorum: %w", err)
+			}
+
+			guarded = true
+			if restarted, err = etcdHealthChange(ctx, r.input, ip); err != nil {
+				tflog.Warn(ctx, "Unable to read the etcd health of "+ip+" before the reboot. Reason "+err.Error())
+			}
+		}
+	}
+
+	conn, err := secureConn(ctx, r.input, net.JoinHostPort(ip, strconv.Itoa(talosPort)))
+	if err != nil {
+		return false, false, fmt.Errorf("unable to make secure connection to Talos machine: %w", err)
+	}
+
+	result, err := applyConfigRequest(ctx, conn, req)
+	if err != nil {
+		return false, false, err
+	}
+	rebooted = result.Mode == machine.ApplyConfigurationRequest_REBOOT
+
+	if guarded && rebooted {
+		if err := waitMemberRestarted(ctx, r.input, ip, restarted); err != nil {
+			return true, true, fmt.Errorf("the node's etcd member didn't recover from the reboot: %w", err)
+		}
+	}
+
+	return true, rebooted, nil
+}
+
+// healthy is the health gate between batches. It requires every control node's etcd member to be healthy and
+// each of the given nodes to be Ready in Kubernetes.
+func (r *clusterRollout) healthy(ctx context.Context, nodes []*talosClusterNode) error {
+	for _, control := range r.controlNodes {
+		conn, err := secureConn(ctx, r.input, net.JoinHostPort(control.ConfigIP.Value, strconv.Itoa(talosPort)))
+		if err != nil {
+			return err
+		}
+
+		err = serviceHealthy(ctx, machine.NewMachineServiceClient(conn), "etcd")
+		conn.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", control.Name.Value, err)
+		}
+	}
+
+	conn, err := secureConn(ctx, r.input, net.JoinHostPort(r.controlNodes[0].ConfigIP.Value, strconv.Itoa(talosPort)))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	resp, err := machine.NewMachineServiceClient(conn).EtcdMemberList(ctx, &machine.EtcdMemberListRequest{})
+	if err != nil {
+		return fmt.Errorf("error getting etcd members: %w", err)
+	}
+
+	for _, msg := range resp.Messages {
+		for _, member := range msg.Members {
+			if member.IsLearner {
+				return fmt.Errorf("etcd member %s is still a learner", member.Hostname)
+			}
+		}
+	}
+
+	k8s, err := newKubernetesClient(r.input)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		if err := k8s.nodeReady(ctx, node.kubernetesName()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// waitHealthy polls the health gate until it passes or nodeHealthTimeout elapses. If one of the nodes rebooted, the
+// gate is only polled once rebootGracePeriod has passed.
+func (r *clusterRollout) waitHealthy(ctx context.Context, nodes []*talosClusterNode, rebooted bool) error {
+	if rebooted {
+		time.Sleep(rebootGracePeriod)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, nodeHealthTimeout)
+	defer cancel()
+
+	for {
+		err := r.healthy(ctx, nodes)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for the cluster to become healthy: %w", err)
+		default:
+			tflog.Info(ctx, "Waiting for cluster health, reason "+err.Error())
+			time.Sleep(componentPollInterval)
+		}
+	}
+}
+
+// rollout applies a change to each node in batches of at most size nodes, waiting for the health gate to pass
+// after each batch. The change function reports whether it changed the node and whether the node rebooted.
+func (r *clusterRollout) rollout(ctx context.Context, nodes []talosClusterNode, size int,
+	change func(*talosClusterNode) (bool, bool, error)) error {
+	for start := 0; start < len(nodes); start += size {
+		end := start + size
+		if end > len(nodes) {
+			end = len(nodes)
+		}
+
+		changed, rebooted := []*talosClusterNode{}, false
+		for i := start; i < end; i++ {
+			applied, reboot, err := change(&nodes[i])
+			if err != nil {
+				return fmt.Errorf("%s: %w", nodes[i].Name.Value, err)
+			}
+			if applied {
+				changed = append(changed, &nodes[i])
+			}
+			rebooted = rebooted || reboot
+		}
+
+		if len(changed) == 0 {
+			continue
+		}
+
+		if err := r.waitHealthy(ctx, changed, rebooted); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t talosClusterResourceType) NewResource(ctx context.Context, in tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	provider, diags := convertProviderType(in)
+	return talosClusterResource{
+		provider: provider,
+	}, diags
+}
+
+type talosClusterResource struct {
+	provider provider
+}
+
+func (r talosClusterResource) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	var (
+		plan talosClusterResourceData
+	)
+
+	if !r.provider.configured {
+		resp.Diagnostics.AddError("Provider not configured.", "The Talos cluster's Create method has been called without the provider being configured. This is a provider bug.")
+		return
+	}
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(plan.ControlNodes) < 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("control_nodes"), "No control nodes.", "At least one control plane node is required.")
+		return
+	}
+
+	rollout := &clusterRollout{baseConfig: plan.BaseConfig}
+	if err := json.Unmarshal([]byte(plan.BaseConfig.Value), &rollout.input); err != nil {
+		resp.Diagnostics.AddError("Failed to unmarshal input bundle.", err.Error())
+		return
+	}
+	ctx = maskSecrets(ctx, rollout.input, &plan)
+
+	// Nodes are saved to state as soon as they're provisioned, so a failure part way through leaves them tracked
+	// rather than running outside of terraform. Terraform then taints the cluster, replacing it on the next apply.
+	provisioned := plan
+	provisioned.ControlNodes, provisioned.WorkerNodes = nil, nil
+	provisioned.Bootstrapped = types.Bool{Value: false}
+	provisioned.ID = types.String{Value: plan.ControlNodes[0].Name.Value}
+	save := func(node *talosClusterNode, control bool) {
+		if control {
+			provisioned.ControlNodes = append(provisioned.ControlNodes, *node)
+			rollout.track(*node)
+		} else {
+			provisioned.WorkerNodes = append(provisioned.WorkerNodes, *node)
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &provisioned)...)
+	}
+
+	// Provision and bootstrap the first control node on its own, the rest of the cluster joins it.
+	first := &plan.ControlNodes[0]
+	if err := rollout.provision(ctx, first, machinetype.TypeControlPlane); err != nil {
+		resp.Diagnostics.AddError("Unable to provision the first control plane node.", err.Error())
+		return
+	}
+	save(first, true)
+
+	conn, err := secureConn(ctx, rollout.input, net.JoinHostPort(first.ConfigIP.Value, strconv.Itoa(talosPort)))
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to make secure connection to Talos machine.", err.Error())
+		return
+	}
+
+	if err := bootstrap(ctx, conn); err != nil {
+		resp.Diagnostics.AddError("issue arised while attempting to bootstrap the machine", err.Error())
+		return
+	}
+	provisioned.Bootstrapped = types.Bool{Value: true}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &provisioned)...)
+
+	if err := rollout.waitHealthy(ctx, []*talosClusterNode{first}, true); err != nil {
+		resp.Diagnostics.AddError("Cluster failed to become healthy after bootstrap.", err.Error())
+		return
+	}
+
+	// Subsequent control nodes are added one by one so that every new etcd member is promoted before the next joins.
+	for i := 1; i < len(plan.ControlNodes); i++ {
+		if err := rollout.provision(ctx, &plan.ControlNodes[i], machinetype.TypeControlPlane); err != nil {
+			resp.Diagnostics.AddError("Unable to provision control plane node "+plan.ControlNodes[i].Name.Value+".", err.Error())
+			return
+		}
+		save(&plan.ControlNodes[i], true)
+
+		if err := rollout.waitHealthy(ctx, []*talosClusterNode{&plan.ControlNodes[i]}, true); err != nil {
+			resp.Diagnostics.AddError("Cluster failed to become healthy.", err.Error())
+			return
+		}
+	}
+
+	size := batchSize(plan.MaxUnavailable, len(plan.WorkerNodes), false)
+	if err := rollout.rollout(ctx, plan.WorkerNodes, size, func(node *talosClusterNode) (bool, bool, error) {
+		if err := rollout.provision(ctx, node, machinetype.TypeWorker); err != nil {
+			return true, true, err
+		}
+		save(node, false)
+
+		return true, true, nil
+	}); err != nil {
+		resp.Diagnostics.AddError("Unable to provision worker nodes.", err.Error())
+		return
+	}
+
+	plan.Bootstrapped = provisioned.Bootstrapped
+	plan.ID = provisioned.ID
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the configuration of every node from the one applied to it.
+func (r talosClusterResource) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	var (
+		state talosClusterResourceData
+	)
+
+	if !r.provider.configured {
+		resp.Diagnostics.AddError("Provider not configured.", "The Talos cluster's Read method has been called without the provider being configured. This is a provider bug.")
+		return
+	}
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	input := generate.Input{}
+	if err := json.Unmarshal([]byte(state.BaseConfig.Value), &input); err != nil {
+		resp.Diagnostics.AddError("error while unmarshalling Talos node base configuration package", err.Error())
+		return
+	}
+	ctx = maskSecrets(ctx, input, &state)
+
+	for i := range state.ControlNodes {
+		resp.Diagnostics.Append(state.ControlNodes[i].read(ctx, input, state.BaseConfig, machinetype.TypeControlPlane)...)
+	}
+	for i := range state.WorkerNodes {
+		resp.Diagnostics.Append(state.WorkerNodes[i].read(ctx, input, state.BaseConfig, machinetype.TypeWorker)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r talosClusterResource) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	var (
+		plan  talosClusterResourceData
+		state talosClusterResourceData
+	)
+
+	if !r.provider.configured {
+		resp.Diagnostics.AddError("Provider not configured.", "The Talos cluster's Update method has been called without the provider being configured. This is a provider bug.")
+		return
+	}
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(plan.ControlNodes) < 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("control_nodes"), "No control nodes.", "At least one control plane node is required.")
+		return
+	}
+
+	// The health gate starts out with the control nodes already in the cluster, new ones are added once applied.
+	rollout := &clusterRollout{baseConfig: plan.BaseConfig, controlNodes: append([]talosClusterNode{}, state.ControlNodes...)}
+	if err := json.Unmarshal([]byte(plan.BaseConfig.Value), &rollout.input); err != nil {
+		resp.Diagnostics.AddError("Failed to unmarshal input bundle.", err.Error())
+		return
+	}
+	ctx = maskSecrets(ctx, rollout.input, &plan)
+
+	prior := map[string]talosClusterNode{}
+	for _, node := range append(append([]talosClusterNode{}, state.ControlNodes...), state.WorkerNodes...) {
+		prior[node.Name.Value] = node
+	}
+
+	apply := func(machineType machinetype.Type) func(*talosClusterNode) (bool, bool, error) {
+		return func(node *talosClusterNode) (applied, rebooted bool, err error) {
+			if old, ok := prior[node.Name.Value]; ok {
+				applied, rebooted, err = rollout.reconfigure(ctx, r.provider, &old, node, machineType)
+			} else {
+				applied, rebooted, err = true, true, rollout.provision(ctx, node, machineType)
+			}
+
+			if err == nil && machineType == machinetype.TypeControlPlane {
+				rollout.track(*node)
+			}
+
+			return applied, rebooted, err
+		}
+	}
+
+	// Control plane nodes are always rolled out before workers.
+	size := batchSize(plan.MaxUnavailable, len(plan.ControlNodes), true)
+	if err := rollout.rollout(ctx, plan.ControlNodes, size, apply(machinetype.TypeControlPlane)); err != nil {
+		resp.Diagnostics.AddError("Unable to roll out control plane changes.", err.Error())
+		return
+	}
+
+	size = batchSize(plan.MaxUnavailable, len(plan.WorkerNodes), false)
+	if err := rollout.rollout(ctx, plan.WorkerNodes, size, apply(machinetype.TypeWorker)); err != nil {
+		resp.Diagnostics.AddError("Unable to roll out worker changes.", err.Error())
+		return
+	}
+
+	// Nodes no longer part of the cluster are removed from it and reset, workers first.
+	current := map[string]bool{}
+	peers := []string{}
+	for _, node := range plan.ControlNodes {
+		current[node.Name.Value] = true
+		peers = append(peers, node.ConfigIP.Value)
+	}
+	for _, node := range plan.WorkerNodes {
+		current[node.Name.Value] = true
+	}
+
+	for i, node := range append(append([]talosClusterNode{}, state.WorkerNodes...), state.ControlNodes...) {
+		if current[node.Name.Value] {
+			continue
+		}
+
+		control := i >= len(state.WorkerNodes)
+		resp.Diagnostics.Append(rollout.remove(ctx, r.provider, node, control, true, peers)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Bootstrapping only ever happens once, when the cluster is created.
+	plan.Bootstrapped = state.Bootstrapped
+	plan.ID = state.ID
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete removes every node from the cluster and resets it, workers first.
+func (r talosClusterResource) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	var (
+		state talosClusterResourceData
+	)
+
+	if !r.provider.configured {
+		resp.Diagnostics.AddError("Provider not configured.", "The Talos cluster's Delete method has been called without the provider being configured. This is a provider bug.")
+		return
+	}
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !resetOnDestroy(state.OnDestroy, &resp.Diagnostics) {
+		return
+	}
+
+	rollout := &clusterRollout{baseConfig: state.BaseConfig, controlNodes: append([]talosClusterNode{}, state.ControlNodes...)}
+	if err := json.Unmarshal([]byte(state.BaseConfig.Value), &rollout.input); err != nil {
+		resp.Diagnostics.AddError("error while unmarshalling Talos node bae configuration package", err.Error())
+		return
+	}
+	ctx = maskSecrets(ctx, rollout.input, &state)
+
+	for _, node := range state.WorkerNodes {
+		resp.Diagnostics.Append(rollout.remove(ctx, r.provider, node, false, false, nil)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Control nodes leave etcd one at a time, last first, so the remaining members always keep their quorum. The
+	// members which haven't left yet are the peers the leaving member is removed through if it can't be reached.
+	for i := len(state.ControlNodes) - 1; i >= 0; i-- {
+		peers := []string{}
+		for _, node := range state.ControlNodes[:i] {
+			peers = append(peers, node.ConfigIP.Value)
+		}
+
+		resp.Diagnostics.Append(rollout.remove(ctx, r.provider, state.ControlNodes[i], true, false, peers)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+}
+
+// ValidateConfig checks the relationships between the network devices and encryption keys of each node, which Talos
+// only rejects once the node is configured.
+func (r talosClusterResource) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var config talosClusterResourceData
+
+	decoded, diags := decodeConfig(ctx, req.Config, &config)
+	resp.Diagnostics.Append(diags...)
+	if !decoded {
+		return
+	}
+
+	for i, node := range config.ControlNodes {
+		resp.Diagnostics.Append(validateTalosConfig(node.TalosConfig, path.Root("control_nodes").AtListIndex(i).AtName("config"))...)
+		resp.Diagnostics.Append(validateApplyMode(node.ApplyMode, node.TryTimeout, path.Root("control_nodes").AtListIndex(i))...)
+		resp.Diagnostics.Append(node.Reset.validate(path.Root("control_nodes").AtListIndex(i).AtName("reset"))...)
+	}
+	for i, node := range config.WorkerNodes {
+		resp.Diagnostics.Append(validateTalosConfig(node.TalosConfig, path.Root("worker_nodes").AtListIndex(i).AtName("config"))...)
+		resp.Diagnostics.Append(validateApplyMode(node.ApplyMode, node.TryTimeout, path.Root("worker_nodes").AtListIndex(i))...)
+		resp.Diagnostics.Append(node.Reset.validate(path.Root("worker_nodes").AtListIndex(i).AtName("reset"))...)
+	}
+}
+
+// ModifyPlan computes the nodes' derived values, such as component images and Wireguard keys, so they are shown in
+// the plan. Each node keeps the values of the node of the same name in state, and its configuration is rendered so
+// that invalid config patches are reported during plan.
+func (r talosClusterResource) ModifyPlan(ctx context.Context, req tfsdk.ModifyResourcePlanRequest, resp *tfsdk.ModifyResourcePlanResponse) {
+	var (
+		plan  talosClusterResourceData
+		state talosClusterResourceData
+		prior *talosClusterResourceData
+	)
+
+	// Nothing to plan when the cluster is being destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	decoded, diags := decodeConfig(ctx, req.Config, &plan)
+	resp.Diagnostics.Append(diags...)
+	if !decoded || plan.BaseConfig.Unknown {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		if diags := req.State.Get(ctx, &state); diags.HasError() {
+			return
+		}
+		prior = &state
+	}
+
+	resp.Diagnostics.Append(plan.generate(prior)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("bootstrapped"), &plan.Bootstrapped)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("id"), &plan.ID)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+
+	rollout := &clusterRollout{baseConfig: plan.BaseConfig}
+	if err := json.Unmarshal([]byte(plan.BaseConfig.Value), &rollout.input); err != nil || !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+	ctx = maskSecrets(ctx, rollout.input, &plan)
+
+	for _, role := range []struct {
+		name        string
+		nodes       []talosClusterNode
+		machineType machinetype.Type
+	}{{"control_nodes", plan.ControlNodes, machinetype.TypeControlPlane}, {"worker_nodes", plan.WorkerNodes, machinetype.TypeWorker}} {
+		for i := range role.nodes {
+			if _, err := rollout.render(&role.nodes[i], role.machineType, false); err != nil {
+				resp.Diagnostics.Append(nodeConfigDiagnostics(path.Root(role.name).AtListIndex(i), "Unable to generate talos node config.", err)...)
+			}
+		}
+	}
+}
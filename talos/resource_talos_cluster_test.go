@@ -0,0 +1,152 @@
+package talos
+
+import (
+	"encoding/json"
+	"reflect"
+	"terraform-provider-talos/talos/datatypes"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	configloader "github.com/talos-systems/talos/pkg/machinery/config/configloader"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
+)
+
+func TestBatchSize(t *testing.T) {
+	tests := []struct {
+		maxUnavailable types.Int64
+		nodes          int
+		control        bool
+		expected       int
+	}{
+		{types.Int64{Null: true}, 5, false, 1},
+		{types.Int64{Value: 3}, 5, false, 3},
+		{types.Int64{Value: 3}, 5, true, 2},
+		{types.Int64{Value: 3}, 3, true, 1},
+		{types.Int64{Value: 2}, 1, true, 1},
+		{types.Int64{Value: 0}, 4, false, 1},
+	}
+
+	for _, tt := range tests {
+		if size := batchSize(tt.maxUnavailable, tt.nodes, tt.control); size != tt.expected {
+			t.Errorf("batchSize(%v, %d, %t): expected %d but got %d", tt.maxUnavailable.Value, tt.nodes, tt.control, tt.expected, size)
+		}
+	}
+}
+
+// TestRolloutTracking checks that the health gate only covers the control nodes which have been applied.
+func TestRolloutTracking(t *testing.T) {
+	rollout := &clusterRollout{controlNodes: []talosClusterNode{
+		{Name: datatypes.Wraps("cp-1"), ConfigIP: datatypes.Wraps("10.0.0.1")},
+	}}
+
+	rollout.track(talosClusterNode{Name: datatypes.Wraps("cp-1"), ConfigIP: datatypes.Wraps("10.0.0.11")})
+	rollout.track(talosClusterNode{Name: datatypes.Wraps("cp-2"), ConfigIP: datatypes.Wraps("10.0.0.2")})
+	if len(rollout.controlNodes) != 2 || rollout.controlNodes[0].ConfigIP.Value != "10.0.0.11" {
+		t.Fatalf("expected the reconfigured node to be replaced and the new one added, got %v", rollout.controlNodes)
+	}
+
+	rollout.untrack("cp-1")
+	if len(rollout.controlNodes) != 1 || rollout.controlNodes[0].Name.Value != "cp-2" {
+		t.Fatalf("expected the removed node to be untracked, got %v", rollout.controlNodes)
+	}
+}
+
+// TestClusterNodeReadNormalized checks that the configuration of a cluster's worker, which is rendered from the
+// control node's data, reads back without drift.
+func TestClusterNodeReadNormalized(t *testing.T) {
+	base, err := json.Marshal(datatypes.InputBundleExample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := talosClusterNode{
+		Name: datatypes.Wraps("worker-1"),
+		TalosConfig: datatypes.TalosConfig{
+			Install: &datatypes.InstallConfig{},
+			Network: &datatypes.NetworkConfig{Hostname: datatypes.Wraps("worker-1")},
+			Sysctls: datatypes.MachineSysctls{"net.ipv4.ip_forward": datatypes.Wraps("1")},
+		},
+	}
+	data := node.nodeData(types.String{Value: string(base)})
+	prior := datatypes.Copy(data.TalosConfig)
+
+	confString, err := genConfig(machine.TypeWorker, &datatypes.InputBundleExample, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := configloader.NewFromBytes(confString)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := readNormalized(data, machine.TypeWorker, &datatypes.InputBundleExample, cfg.Raw().(*v1alpha1.Config)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(prior, data.TalosConfig) {
+		t.Fatalf("expected no drift, got %+v", data.TalosConfig)
+	}
+}
+
+// TestClusterGenerateByName checks that nodes keep the derived values of the node of the same name in state when
+// nodes are removed or reordered.
+func TestClusterGenerateByName(t *testing.T) {
+	base, err := json.Marshal(datatypes.InputBundleExample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := map[string]string{}
+	node := func(name string, key types.String) talosClusterNode {
+		return talosClusterNode{
+			Name: datatypes.Wraps(name),
+			TalosConfig: datatypes.TalosConfig{Network: &datatypes.NetworkConfig{
+				Hostname: datatypes.Wraps(name),
+				Devices: []datatypes.NetworkDevice{{
+					Name:      datatypes.Wraps("wg0"),
+					Wireguard: &datatypes.Wireguard{PrivateKey: key},
+				}},
+			}},
+		}
+	}
+	key := func(node talosClusterNode) string {
+		return node.Network.Devices[0].Wireguard.PrivateKey.Value
+	}
+
+	prior := &talosClusterResourceData{
+		BaseConfig:   types.String{Value: string(base)},
+		ControlNodes: []talosClusterNode{node("cp-1", types.String{Null: true}), node("cp-2", types.String{Null: true})},
+		WorkerNodes:  []talosClusterNode{node("worker-1", types.String{Null: true}), node("worker-2", types.String{Null: true})},
+	}
+	if diags := prior.generate(nil); diags.HasError() {
+		t.Fatal(diags)
+	}
+	for _, nodes := range [][]talosClusterNode{prior.ControlNodes, prior.WorkerNodes} {
+		for _, n := range nodes {
+			keys[n.Name.Value] = key(n)
+		}
+	}
+
+	plan := &talosClusterResourceData{
+		BaseConfig:   prior.BaseConfig,
+		ControlNodes: []talosClusterNode{node("cp-2", types.String{Null: true}), node("cp-1", types.String{Null: true})},
+		WorkerNodes:  []talosClusterNode{node("worker-2", types.String{Null: true})},
+	}
+	if diags := plan.generate(prior); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	for _, nodes := range [][]talosClusterNode{plan.ControlNodes, plan.WorkerNodes} {
+		for _, n := range nodes {
+			if key(n) != keys[n.Name.Value] {
+				t.Errorf("expected %s to keep its Wireguard private key", n.Name.Value)
+			}
+		}
+	}
+	if plan.ControlNodes[0].ControllerManager == nil || plan.ControlNodes[0].ControllerManager.Image.Value == "" {
+		t.Error("expected the control node's images to be generated")
+	}
+	if plan.WorkerNodes[0].ControllerManager != nil {
+		t.Error("expected the worker not to be given control plane settings")
+	}
+}
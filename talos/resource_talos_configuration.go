@@ -23,6 +23,7 @@ import (
 var _ tfsdk.ResourceType = talosClusterConfigResourceType{}
 var _ tfsdk.Resource = talosClusterConfigResource{}
 var _ tfsdk.ResourceWithImportState = talosClusterConfigResource{}
+var _ tfsdk.ResourceWithValidateConfig = talosClusterConfigResource{}
 
 type talosClusterConfigResourceType struct{}
 
@@ -46,6 +47,9 @@ func (t talosClusterConfigResourceType) GetSchema(ctx context.Context) (tfsdk.Sc
 				},
 				Required:            true,
 				MarkdownDescription: "A list of that the talosctl client will connect to. Can be a DNS hostname or an IP address and may include a port number. Must begin with \"https://\".",
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateURL("https"),
+				},
 			},
 			"kubernetes_endpoint": {
 				Type:     types.StringType,
@@ -74,18 +78,27 @@ func (t talosClusterConfigResourceType) GetSchema(ctx context.Context) (tfsdk.Sc
 			"service_domain": {
 				Type:     types.StringType,
 				Optional: true,
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateDomain(),
+				},
 			},
 			"pod_network": {
 				Type: types.ListType{
 					ElemType: types.StringType,
 				},
 				Optional: true,
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateCIDR(),
+				},
 			},
 			"service_network": {
 				Type: types.ListType{
 					ElemType: types.StringType,
 				},
 				Optional: true,
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateCIDR(),
+				},
 			},
 			"kubernetes_version": {
 				Type:                types.StringType,
@@ -95,6 +108,9 @@ func (t talosClusterConfigResourceType) GetSchema(ctx context.Context) (tfsdk.Sc
 			"external_etcd": {
 				Type:     types.BoolType,
 				Optional: true,
+				MarkdownDescription: "Unsupported. Talos v1.1 renders the apiserver's etcd flags itself and refuses to have them " +
+					"overridden, so the cluster's apiservers always use the etcd run by Talos. Only `false` is accepted.",
+				DeprecationMessage: "External etcd is unsupported by Talos v1.1 and the attribute has no effect, it will be removed.",
 			},
 			"install": {
 				Optional:    true,
@@ -468,6 +484,21 @@ func (r talosClusterConfigResource) Delete(ctx context.Context, req tfsdk.Delete
 	}
 }
 
+// ValidateConfig rejects external etcd, which the Talos version the provider is built against can't run.
+func (r talosClusterConfigResource) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var externalEtcd types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("external_etcd"), &externalEtcd)...)
+	if resp.Diagnostics.HasError() || externalEtcd.Null || externalEtcd.Unknown {
+		return
+	}
+
+	if externalEtcd.Value {
+		resp.Diagnostics.AddAttributeError(path.Root("external_etcd"), "External etcd is unsupported.",
+			"Talos v1.1 renders the apiserver's etcd flags itself and refuses to have them overridden, so the cluster's "+
+				"apiservers can't be pointed at an etcd cluster which isn't run by Talos.")
+	}
+}
+
 func (r talosClusterConfigResource) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
 	tfsdk.ResourceImportStatePassthroughID(ctx, path.Root("Id"), req, resp)
 }
@@ -7,10 +7,10 @@ import (
 	"net"
 	"strconv"
 	"terraform-provider-talos/talos/datatypes"
+	"time"
 
-	"github.com/davecgh/go-spew/spew"
 	v1alpha1 "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
-	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
 
 	"github.com/talos-systems/talos/pkg/machinery/api/machine"
 
@@ -21,11 +21,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ tfsdk.ResourceType = talosControlNodeResourceType{}
 var _ tfsdk.Resource = talosControlNodeResource{}
 var _ tfsdk.ResourceWithImportState = talosControlNodeResource{}
+var _ tfsdk.ResourceWithModifyPlan = talosControlNodeResource{}
+var _ tfsdk.ResourceWithValidateConfig = talosControlNodeResource{}
 
 type talosControlNodeResourceType struct{}
 
@@ -37,17 +40,22 @@ func (t talosControlNodeResourceType) GetSchema(_ context.Context) (tfsdk.Schema
 			"name": {
 				Type:     types.StringType,
 				Required: true,
-				// ValidateFunc: validateDomain,
 				// ForceNew: true,
-				// TODO validate and fix forcenew
+				// TODO fix forcenew
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateDomain(),
+				},
 			},
 			"provision_ip": {
 				Type:        types.StringType,
 				Description: "IP address of the machine to be provisioned.",
 				Required:    true,
-				// TODO validate and forcenew
+				// TODO forcenew
 				// ForceNew: false
 				// doesn't matter if changed after initial creation.
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateIP(),
+				},
 			},
 			// --- MachineConfig.
 			// See https://www.talos.dev/v1.0/reference/configuration/#machineconfig for full spec.
@@ -70,7 +78,24 @@ func (t talosControlNodeResourceType) GetSchema(_ context.Context) (tfsdk.Schema
 			"configure_ip": {
 				Type:     types.StringType,
 				Required: true,
-				// ValidateFunc: validateIP,
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateIP(),
+				},
+			},
+			"config_patches": configPatchesAttribute,
+			"reset":          resetAttribute,
+			"on_destroy":     onDestroyAttribute,
+			"on_unreachable": onUnreachableAttribute,
+			"runtime_mode":   runtimeModeAttribute,
+			"apply_mode":     applyModeAttributes["apply_mode"],
+			"try_timeout":    applyModeAttributes["try_timeout"],
+			"applied_mode":   applyModeAttributes["applied_mode"],
+			"rebooted":       applyModeAttributes["rebooted"],
+			"force": {
+				Type:     types.BoolType,
+				Optional: true,
+				MarkdownDescription: "Skip the check that destroying the node, or applying a change which reboots it, leaves a quorum of healthy etcd " +
+					"members. Only meant for disaster recovery. Defaults to `false`.",
 			},
 
 			// From the cluster provider
@@ -78,16 +103,9 @@ func (t talosControlNodeResourceType) GetSchema(_ context.Context) (tfsdk.Schema
 				Type:      types.StringType,
 				Required:  true,
 				Sensitive: true,
-				/*
-					ValidateFunc: func(value interface{}, key string) (warns []string, errs []error) {
-						v := value.(string)
-						input := generate.Input{}
-						if err := json.Unmarshal([]byte(v), &input); err != nil {
-							errs = append(errs, fmt.Errorf("Failed to  base_config. Do not set this value to anything other than the base_config value of a talos_cluster_config resource"))
-						}
-						return
-					},
-				*/
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateBaseConfig(),
+				},
 			},
 
 			// Generated
@@ -115,124 +133,133 @@ type talosControlNodeResourceData struct {
 
 	datatypes.TalosConfig `tfsdk:"config"`
 
-	Bootstrap   types.Bool   `tfsdk:"bootstrap"`
-	ProvisionIP types.String `tfsdk:"provision_ip"`
-	ConfigIP    types.String `tfsdk:"configure_ip"`
-	BaseConfig  types.String `tfsdk:"base_config"`
-	ID          types.String `tfsdk:"id"`
+	Bootstrap     types.Bool     `tfsdk:"bootstrap"`
+	ProvisionIP   types.String   `tfsdk:"provision_ip"`
+	ConfigIP      types.String   `tfsdk:"configure_ip"`
+	Patches       []types.String `tfsdk:"config_patches"`
+	Reset         *resetOptions  `tfsdk:"reset"`
+	OnDestroy     types.String   `tfsdk:"on_destroy"`
+	OnUnreachable types.String   `tfsdk:"on_unreachable"`
+	RuntimeMode   types.String   `tfsdk:"runtime_mode"`
+	Force         types.Bool     `tfsdk:"force"`
+	ApplyMode     types.String   `tfsdk:"apply_mode"`
+	TryTimeout    types.String   `tfsdk:"try_timeout"`
+	AppliedMode   types.String   `tfsdk:"applied_mode"`
+	Rebooted      types.Bool     `tfsdk:"rebooted"`
+	BaseConfig    types.String   `tfsdk:"base_config"`
+	ID            types.String   `tfsdk:"id"`
 }
 
+// Generate fills in the values derived from the node's base_config and the Talos defaults which aren't configured.
 func (plan *talosControlNodeResourceData) Generate() (err error) {
+	return plan.generate(nil)
+}
+
+// generate fills in the node's derived values which aren't configured. Wireguard private keys which aren't
+// configured are taken from prior, the node's previous state, if there is one.
+func (plan *talosControlNodeResourceData) generate(prior *talosControlNodeResourceData) (err error) {
 	input := generate.Input{}
 	if err := json.Unmarshal([]byte(plan.BaseConfig.Value), &input); err != nil {
 		return fmt.Errorf("unable to marshal node's base_config data into it's generate.Input struct: %w", err)
 	}
 
-	// Generate wireguard keys.
-	for _, device := range plan.Network.Devices {
-		// If the device's wireguard configuration exists, derive the public key from it's private key.
-		if device.Wireguard != nil {
-			var pk wgtypes.Key
-			// If a key doesn't exist make one, otherwise generate one.
-			if device.Wireguard.PrivateKey.Null {
-				pk, err = wgtypes.GeneratePrivateKey()
-				device.Wireguard.PrivateKey = types.String{Value: pk.String()}
-			} else {
-				pk, err = wgtypes.ParseKey(device.Wireguard.PrivateKey.Value)
-			}
-
-			if err != nil {
-				return err
-			}
-
-			device.Wireguard.PublicKey = types.String{Value: pk.PublicKey().String()}
+	if plan.Network != nil {
+		var devices []datatypes.NetworkDevice
+		if prior != nil && prior.Network != nil {
+			devices = prior.Network.Devices
+		}
+		if err := generateWireguardKeys(plan.Network.Devices, devices); err != nil {
+			return err
 		}
 	}
 
-	// TODO derive these from talos machinery
 	if plan.ControlPlane == nil {
 		plan.ControlPlane = &datatypes.ControlPlaneConfig{}
 	}
-	plan.ControlPlane.Endpoint = types.String{Value: input.GetControlPlaneEndpoint()}
+	fillString(&plan.ControlPlane.Endpoint, input.GetControlPlaneEndpoint())
 
+	// Images which aren't pinned are those Talos generates for the cluster's kubernetes_version. CoreDNS and etcd are
+	// versioned independently of Kubernetes and default to the versions built into Talos.
 	if plan.ControllerManager == nil {
 		plan.ControllerManager = &datatypes.ControllerManagerConfig{}
 	}
-	plan.ControllerManager.Image = types.String{Value: (&v1alpha1.ControllerManagerConfig{}).Image()}
+	fillString(&plan.ControllerManager.Image, kubernetesImage(input, constants.KubernetesControllerManagerImage, (&v1alpha1.ControllerManagerConfig{}).Image()))
 
 	if plan.CoreDNS == nil {
 		plan.CoreDNS = &datatypes.CoreDNS{}
 	}
-	plan.CoreDNS.Image = types.String{Value: (&v1alpha1.CoreDNS{}).Image()}
+	fillString(&plan.CoreDNS.Image, (&v1alpha1.CoreDNS{}).Image())
 
-	plan.AllowSchedulingOnMasters = types.Bool{Value: input.AllowSchedulingOnMasters}
+	if plan.AllowSchedulingOnMasters.Null {
+		plan.AllowSchedulingOnMasters = types.Bool{Value: input.AllowSchedulingOnMasters}
+	}
 
 	if plan.Kubelet == nil {
 		plan.Kubelet = &datatypes.KubeletConfig{}
 	}
-	plan.Kubelet.Image = types.String{Value: (&v1alpha1.KubeletConfig{}).Image()}
+	fillString(&plan.Kubelet.Image, kubernetesImage(input, constants.KubeletImage, (&v1alpha1.KubeletConfig{}).Image()))
 
 	if plan.Proxy == nil {
 		plan.Proxy = &datatypes.ProxyConfig{}
 	}
-	plan.Proxy.Image = types.String{Value: (&v1alpha1.ProxyConfig{}).Image()}
+	fillString(&plan.Proxy.Image, kubernetesImage(input, constants.KubeProxyImage, (&v1alpha1.ProxyConfig{}).Image()))
 
 	if plan.Scheduler == nil {
 		plan.Scheduler = &datatypes.SchedulerConfig{}
 	}
-	plan.Scheduler.Image = types.String{Value: (&v1alpha1.SchedulerConfig{}).Image()}
+	fillString(&plan.Scheduler.Image, kubernetesImage(input, constants.KubernetesSchedulerImage, (&v1alpha1.SchedulerConfig{}).Image()))
 
 	if plan.APIServer == nil {
-		plan.APIServer = &datatypes.APIServerConfig{}
-	}
-
-	plan.APIServer.Image = types.String{Value: (&v1alpha1.APIServerConfig{}).Image()}
-	for _, san := range input.GetAPIServerSANs() {
-		plan.APIServer.CertSANS = append(plan.APIServer.CertSANS, types.String{Value: san})
-	}
-	plan.APIServer.DisablePSP = types.Bool{Value: true}
-	plan.APIServer.AdmissionPlugins = []datatypes.AdmissionPluginConfig{
-		{
-			Name: types.String{Value: "PodSecurity"},
-			Configuration: types.String{Value: `apiVersion: pod-security.admission.config.k8s.io/v1alpha1
-defaults:
-    audit: restricted
-    audit-version: latest
-    enforce: baseline
-    enforce-version: latest
-    warn: restricted
-    warn-version: latest
-exemptions:
-    namespaces:
-        - kube-system
-    runtimeClasses: []
-    usernames: []
-kind: PodSecurityConfiguration`},
-		},
+		plan.APIServer = &datatypes.APIServerConfig{DisablePSP: types.Bool{Null: true}}
 	}
 
-	plan.Install.Image = types.String{Value: input.InstallImage}
-	if input.InstallImage == "" {
-		plan.Install.Image = types.String{Value: generate.DefaultGenOptions().InstallImage}
+	fillString(&plan.APIServer.Image, kubernetesImage(input, constants.KubernetesAPIServerImage, (&v1alpha1.APIServerConfig{}).Image()))
+	if plan.APIServer.CertSANS == nil {
+		for _, san := range input.GetAPIServerSANs() {
+			plan.APIServer.CertSANS = append(plan.APIServer.CertSANS, types.String{Value: san})
+		}
+	}
+	if plan.APIServer.DisablePSP.Null {
+		plan.APIServer.DisablePSP = types.Bool{Value: true}
+	}
+	// Pod Security is only defaulted when no admission plugins are configured at all, configured ones are kept as is.
+	if plan.APIServer.AdmissionPlugins == nil {
+		if plan.APIServer.PodSecurity == nil {
+			plan.APIServer.PodSecurity = datatypes.PodSecurityDefaults()
+		}
+		plan.APIServer.AdmissionPlugins = []datatypes.AdmissionPluginConfig{}
 	}
 
-	if plan.Discovery == nil {
-		plan.Discovery = &datatypes.ClusterDiscoveryConfig{}
+	if plan.Install != nil {
+		fillString(&plan.Install.Image, defaultInstallImage(input))
 	}
 
-	plan.Discovery.Enabled = types.Bool{Value: input.DiscoveryEnabled}
+	if plan.Discovery == nil {
+		plan.Discovery = &datatypes.ClusterDiscoveryConfig{Enabled: types.Bool{Null: true}}
+	}
+	if plan.Discovery.Enabled.Null {
+		plan.Discovery.Enabled = types.Bool{Value: input.DiscoveryEnabled}
+	}
 
+	// The etcd CA key is taken from the base configuration when rendering, it isn't copied into state.
 	if plan.Etcd == nil {
-		plan.Etcd = &datatypes.EtcdConfig{}
+		plan.Etcd = &datatypes.EtcdConfig{CaKey: types.String{Null: true}, Subnet: types.String{Null: true}}
 	}
 
-	plan.Etcd.Image = types.String{Value: (&v1alpha1.EtcdConfig{}).Image()}
-	plan.Etcd.CaCrt = types.String{Value: string(input.Certs.Etcd.Crt)}
-	plan.Etcd.CaKey = types.String{Value: string(input.Certs.Etcd.Key)}
+	fillString(&plan.Etcd.Image, (&v1alpha1.EtcdConfig{}).Image())
+	fillString(&plan.Etcd.CaCrt, string(input.Certs.Etcd.Crt))
 
 	return
 }
 
+func (plan *talosControlNodeResourceData) ConfigPatches() []types.String {
+	return plan.Patches
+}
+
+func (plan *talosControlNodeResourceData) Runtime() (runtimeMode, error) {
+	return parseRuntimeMode(plan.RuntimeMode)
+}
+
 func (plan *talosControlNodeResourceData) ReadInto(in *v1alpha1.Config) (err error) {
 	if in == nil {
 		return
@@ -243,11 +270,12 @@ func (plan *talosControlNodeResourceData) ReadInto(in *v1alpha1.Config) (err err
 		datatypes.TalosProxyConfig{ProxyConfig: in.ClusterConfig.ProxyConfig},
 		datatypes.TalosRegistriesConfig{RegistriesConfig: &in.MachineConfig.MachineRegistries},
 		datatypes.TalosMCPConfig{MachineControlPlaneConfig: in.MachineConfig.MachineControlPlane},
+		datatypes.TalosFeaturesConfig{FeaturesConfig: in.MachineConfig.MachineFeatures},
 		datatypes.TalosSystemDiskEncryptionConfig{SystemDiskEncryptionConfig: in.MachineConfig.MachineSystemDiskEncryption},
 		datatypes.TalosInstallConfig{InstallConfig: in.MachineConfig.MachineInstall},
 		datatypes.TalosMachineDisk{MachineDisks: in.MachineConfig.MachineDisks},
 		datatypes.TalosNetworkConfig{NetworkConfig: in.MachineConfig.MachineNetwork},
-		datatypes.TalosAPIServerConfig{APIServerConfig: in.ClusterConfig.APIServerConfig},
+		datatypes.TalosAPIServerConfig{APIServerConfig: in.ClusterConfig.APIServerConfig, Files: in.MachineConfig.MachineFiles},
 		datatypes.TalosControlPlaneConfig{ControlPlaneConfig: in.ClusterConfig.ControlPlane},
 		datatypes.TalosMachineSysfs(in.MachineConfig.MachineSysfs),
 		datatypes.TalosMachineSysctls(in.MachineConfig.MachineSysctls),
@@ -295,6 +323,7 @@ func (plan *talosControlNodeResourceData) TalosData(in *v1alpha1.Config) (out *v
 		plan.Proxy,
 		plan.Registry,
 		plan.MachineControlPlane,
+		plan.Features,
 		plan.Encryption,
 		plan.Install,
 		plan.Network,
@@ -355,7 +384,7 @@ func (r talosControlNodeResource) Create(ctx context.Context, req tfsdk.CreateRe
 		return
 	}
 
-	diags := req.Config.Get(ctx, &plan)
+	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -367,6 +396,7 @@ func (r talosControlNodeResource) Create(ctx context.Context, req tfsdk.CreateRe
 		resp.Diagnostics.AddError("Failed to unmarshal input bundle.", err.Error())
 		return
 	}
+	ctx = maskSecrets(ctx, input, &plan)
 
 	if err := plan.Generate(); err != nil {
 		resp.Diagnostics.AddError("Unable to generate initial plan configuration values.", err.Error())
@@ -375,10 +405,20 @@ func (r talosControlNodeResource) Create(ctx context.Context, req tfsdk.CreateRe
 
 	yaml, err := genConfig(machinetype.TypeControlPlane, &input, &plan)
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to generate talos node config.", err.Error())
+		resp.Diagnostics.Append(configDiagnostics("Unable to generate talos node config.", err)...)
+		return
+	}
+
+	applyReq, err := applyRequest(yaml, plan.ApplyMode, plan.TryTimeout, machine.ApplyConfigurationRequest_REBOOT)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid apply mode.", err.Error())
 		return
 	}
 
+	if warning := maintenanceRequest(applyReq); warning != "" {
+		resp.Diagnostics.AddWarning("Apply mode not supported during provisioning.", warning)
+	}
+
 	// Setup connection to maintainence endpoint and apply initial configuration.
 	conn, err := insecureConn(ctx, net.JoinHostPort(plan.ProvisionIP.Value, strconv.Itoa(talosPort)))
 	if err != nil {
@@ -386,11 +426,16 @@ func (r talosControlNodeResource) Create(ctx context.Context, req tfsdk.CreateRe
 		return
 	}
 
-	err = applyConfig(ctx, conn, yaml, machine.ApplyConfigurationRequest_REBOOT)
+	applied, err := applyConfigRequest(ctx, conn, applyReq)
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to apply node configuration yaml", err.Error())
 		return
 	}
+	plan.AppliedMode, plan.Rebooted = appliedResult(applied)
+
+	for _, warning := range applied.Warnings {
+		resp.Diagnostics.AddWarning("Talos configuration warning.", warning)
+	}
 
 	// Setup secure connection to talos API and bootstrap the node if applicable.
 	if plan.Bootstrap.Value {
@@ -406,8 +451,6 @@ func (r talosControlNodeResource) Create(ctx context.Context, req tfsdk.CreateRe
 		}
 	}
 
-	fmt.Println(spew.Sdump(plan.APIServer))
-
 	plan.ID = types.String{Value: string(plan.Name.Value)}
 	diags = resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -431,20 +474,29 @@ func (r talosControlNodeResource) Read(ctx context.Context, req tfsdk.ReadResour
 		return
 	}
 
-	if !r.provider.skipread {
-		conf, errDesc, err := readConfig(ctx, &state, readData{
-			ConfigIP:   state.ConfigIP.Value,
-			BaseConfig: state.BaseConfig.Value,
-		})
-		if err != nil {
-			resp.Diagnostics.AddError(errDesc, err.Error())
-			return
-		}
+	input := generate.Input{}
+	if err := json.Unmarshal([]byte(state.BaseConfig.Value), &input); err != nil {
+		resp.Diagnostics.AddError("error while unmarshalling Talos node base configuration package", err.Error())
+		return
+	}
+	ctx = maskSecrets(ctx, input, &state)
 
-		if err = state.ReadInto(conf); err != nil {
-			resp.Diagnostics.AddError("Error reading talos configuration.", err.Error())
-			return
-		}
+	if unreachable(ctx, state.OnUnreachable, input, state.ConfigIP.Value, resp) {
+		return
+	}
+
+	conf, errDesc, err := readConfig(ctx, &state, readData{
+		ConfigIP:   state.ConfigIP.Value,
+		BaseConfig: state.BaseConfig.Value,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(errDesc, err.Error())
+		return
+	}
+
+	if err = readNormalized(&state, machinetype.TypeControlPlane, &input, conf); err != nil {
+		resp.Diagnostics.AddError("Error reading talos configuration.", err.Error())
+		return
 	}
 
 	diags = resp.State.Set(ctx, &state)
@@ -474,38 +526,84 @@ func (r talosControlNodeResource) Update(ctx context.Context, req tfsdk.UpdateRe
 		resp.Diagnostics.AddError("unmarshal error", "failed to unmarshal input bundle")
 		return
 	}
+	ctx = maskSecrets(ctx, input, &state)
 
 	yaml, err := genConfig(machinetype.TypeControlPlane, &input, &state)
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to generate talos node config.", err.Error())
+		resp.Diagnostics.Append(configDiagnostics("Unable to generate talos node config.", err)...)
+		return
+	}
+
+	applyReq, err := applyRequest(yaml, state.ApplyMode, state.TryTimeout, machine.ApplyConfigurationRequest_AUTO)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid apply mode.", err.Error())
 		return
 	}
 
 	ip := state.ConfigIP.Value
 	host := net.JoinHostPort(ip, strconv.Itoa(talosPort))
 
+	// A reboot checked against etcd quorum holds the provider's etcd lock until the node's member is healthy again.
+	guarded, restarted := false, time.Time{}
+	if !state.Force.Value {
+		reboots, err := rebootsNode(ctx, input, ip, applyReq)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to determine whether the change reboots the node.", err.Error())
+			return
+		}
+
+		if reboots {
+			defer r.provider.lockEtcd(ctx)()
+
+			name := kubernetesNodeName(state.Network, state.Name)
+			if err := checkQuorum(ctx, input, ip, name, controlPlanePeers(ctx, input, ip), false); err != nil {
+				resp.Diagnostics.AddError("Rebooting the node would break etcd quorum.",
+					err.Error()+"\n\nSet force to apply the change regardless.")
+				return
+			}
+
+			guarded = true
+			if restarted, err = etcdHealthChange(ctx, input, ip); err != nil {
+				tflog.Warn(ctx, "Unable to read the etcd health of "+ip+" before the reboot. Reason "+err.Error())
+			}
+		}
+	}
+
 	conn, err := secureConn(ctx, input, host)
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to make secure connection to Talos machine.", err.Error())
 		return
 	}
 
-	err = applyConfig(ctx, conn, yaml, machine.ApplyConfigurationRequest_AUTO)
+	applied, err := applyConfigRequest(ctx, conn, applyReq)
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to apply node configuration yaml", err.Error())
 		return
 	}
+	state.AppliedMode, state.Rebooted = appliedResult(applied)
 
-	if !r.provider.skipread {
-		talosConf, errDesc, err := readConfig(ctx, &state, readData{
-			ConfigIP:   state.ConfigIP.Value,
-			BaseConfig: state.BaseConfig.Value,
-		})
-		if err != nil {
-			resp.Diagnostics.AddError(errDesc, err.Error())
+	for _, warning := range applied.Warnings {
+		resp.Diagnostics.AddWarning("Talos configuration warning.", warning)
+	}
+
+	if guarded && applied.Mode == machine.ApplyConfigurationRequest_REBOOT {
+		if err := waitMemberRestarted(ctx, input, ip, restarted); err != nil {
+			resp.Diagnostics.AddError("The node's etcd member didn't recover from the reboot.", err.Error())
 			return
 		}
-		state.ReadInto(talosConf)
+	}
+
+	talosConf, errDesc, err := readConfig(ctx, &state, readData{
+		ConfigIP:   state.ConfigIP.Value,
+		BaseConfig: state.BaseConfig.Value,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(errDesc, err.Error())
+		return
+	}
+	if err = readNormalized(&state, machinetype.TypeControlPlane, &input, talosConf); err != nil {
+		resp.Diagnostics.AddError("Error reading talos configuration.", err.Error())
+		return
 	}
 
 	state.ID = types.String{Value: string(state.Name.Value)}
@@ -531,32 +629,32 @@ func (r talosControlNodeResource) Delete(ctx context.Context, req tfsdk.DeleteRe
 		return
 	}
 
-	if r.provider.skipdelete {
+	if !resetOnDestroy(state.OnDestroy, &resp.Diagnostics) {
 		return
 	}
 
-	host := net.JoinHostPort(state.ConfigIP.Value, strconv.Itoa(talosPort))
-
 	input := generate.Input{}
 	if err := json.Unmarshal([]byte(state.BaseConfig.Value), &input); err != nil {
 		resp.Diagnostics.AddError("error while unmarshalling Talos node bae configuration package", err.Error())
 		return
 	}
+	ctx = maskSecrets(ctx, input, &state)
 
-	conn, err := secureConn(ctx, input, host)
-	if err != nil {
-		resp.Diagnostics.AddError("error while attempting to connect to Talos API endpoint", err.Error())
-		return
+	// The node's member is only gone from etcd once it has been reset, so the etcd lock is held until then.
+	defer r.provider.lockEtcd(ctx)()
+
+	name := kubernetesNodeName(state.Network, state.Name)
+	peers := controlPlanePeers(ctx, input, state.ConfigIP.Value)
+	if !state.Force.Value {
+		if err := checkQuorum(ctx, input, state.ConfigIP.Value, name, peers, true); err != nil {
+			resp.Diagnostics.AddError("Destroying the node would break etcd quorum.",
+				err.Error()+"\n\nSet force to destroy the node regardless.")
+			return
+		}
 	}
-	defer conn.Close()
 
-	client := machine.NewMachineServiceClient(conn)
-	_, err = client.Reset(ctx, &machine.ResetRequest{
-		Graceful: false,
-		Reboot:   true,
-	})
-	if err != nil {
-		resp.Diagnostics.AddError("error while attempting to connect to reset machine", err.Error())
+	resp.Diagnostics.Append(decommissionNode(ctx, input, state.ConfigIP.Value, name, true, peers, state.Reset, state.ProvisionIP.Value)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -580,6 +678,109 @@ func (r talosControlNodeResource) Delete(ctx context.Context, req tfsdk.DeleteRe
 	}
 }
 
+// ModifyPlan computes the node's derived values, such as component images and Wireguard keys, so they are shown in
+// the plan. Changes to an existing node are dry run so their effect, such as whether the node will reboot, is shown
+// as warnings in the plan.
+func (r talosControlNodeResource) ModifyPlan(ctx context.Context, req tfsdk.ModifyResourcePlanRequest, resp *tfsdk.ModifyResourcePlanResponse) {
+	var (
+		plan  talosControlNodeResourceData
+		state talosControlNodeResourceData
+		prior *talosControlNodeResourceData
+	)
+
+	// Nothing to plan when the node is being destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	decoded, diags := decodeConfig(ctx, req.Config, &plan)
+	resp.Diagnostics.Append(diags...)
+	if !decoded || plan.BaseConfig.Unknown {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		if diags := req.State.Get(ctx, &state); diags.HasError() {
+			return
+		}
+		prior = &state
+	}
+
+	if err := plan.generate(prior); err != nil {
+		resp.Diagnostics.AddError("Unable to generate the node's derived configuration values.", err.Error())
+		return
+	}
+
+	planGenerated(ctx, &plan, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	input := generate.Input{}
+	if err := json.Unmarshal([]byte(plan.BaseConfig.Value), &input); err != nil {
+		return
+	}
+	ctx = maskSecrets(ctx, input, &plan)
+
+	after, err := genConfig(machinetype.TypeControlPlane, &input, &plan)
+	if err != nil {
+		resp.Diagnostics.Append(configDiagnostics("Unable to generate talos node config.", err)...)
+		return
+	}
+
+	config := path.Root("config")
+	resp.Diagnostics.Append(warnImageVersions(input, []componentImage{
+		{config.AtName("kubelet").AtName("image"), plan.Kubelet.Image},
+		{config.AtName("apiserver").AtName("image"), plan.APIServer.Image},
+		{config.AtName("controller_manager").AtName("image"), plan.ControllerManager.Image},
+		{config.AtName("scheduler").AtName("image"), plan.Scheduler.Image},
+		{config.AtName("proxy").AtName("image"), plan.Proxy.Image},
+	})...)
+
+	// Configuration holding values which aren't known yet is validated once they are.
+	if req.Config.Raw.IsFullyKnown() {
+		resp.Diagnostics.Append(validateRendered(plan.RuntimeMode, after)...)
+	}
+
+	// Nothing to dry run when the node is being created.
+	if resp.Diagnostics.HasError() || prior == nil {
+		return
+	}
+
+	before, err := genConfig(machinetype.TypeControlPlane, &input, &state)
+	if err != nil {
+		return
+	}
+
+	if string(before) == string(after) {
+		return
+	}
+
+	applyReq, err := applyRequest(after, plan.ApplyMode, plan.TryTimeout, machine.ApplyConfigurationRequest_AUTO)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("apply_mode"), "Invalid apply mode.", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(dryRun(ctx, input, plan.Name.Value, state.ConfigIP.Value, before, after, applyReq)...)
+}
+
+// ValidateConfig checks the relationships between the node's network devices, encryption keys, kubelet arguments and
+// install disk, which Talos only rejects once the node is configured.
+func (r talosControlNodeResource) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var config talosControlNodeResourceData
+
+	decoded, diags := decodeConfig(ctx, req.Config, &config)
+	resp.Diagnostics.Append(diags...)
+	if !decoded {
+		return
+	}
+
+	resp.Diagnostics.Append(validateTalosConfig(config.TalosConfig, path.Root("config"))...)
+	resp.Diagnostics.Append(validateApplyMode(config.ApplyMode, config.TryTimeout, path.Empty())...)
+	resp.Diagnostics.Append(config.Reset.validate(path.Root("reset"))...)
+}
+
 func (r talosControlNodeResource) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
 	tfsdk.ResourceImportStatePassthroughID(ctx, path.Root("Id"), req, resp)
 }
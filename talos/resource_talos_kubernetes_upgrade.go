@@ -0,0 +1,718 @@
+package talos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/api/resource"
+	v1alpha1 "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gopkg.in/yaml.v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-talos/talos/datatypes"
+)
+
+var _ tfsdk.ResourceType = talosKubernetesUpgradeResourceType{}
+var _ tfsdk.Resource = talosKubernetesUpgradeResource{}
+var _ tfsdk.ResourceWithImportState = talosKubernetesUpgradeResource{}
+var _ tfsdk.ResourceWithModifyPlan = talosKubernetesUpgradeResource{}
+
+var (
+	// componentHealthTimeout is how long a single component on a single node is given to become healthy after
+	// its image has been changed.
+	componentHealthTimeout = 10 * time.Minute
+	componentPollInterval  = 5 * time.Second
+)
+
+type talosKubernetesUpgradeResourceType struct{}
+
+func (t talosKubernetesUpgradeResourceType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Upgrades the Kubernetes components of a running Talos cluster from one version to another, " +
+			"node by node, in the same order as `talosctl upgrade-k8s`. The versions configured on the nodes are read back into `drift`, so a " +
+			"node that drifted to another version is upgraded again on the next apply. Destroying the resource leaves the cluster running " +
+			"the version it was upgraded to.",
+		Attributes: map[string]tfsdk.Attribute{
+			"from_version": {
+				Type:                types.StringType,
+				Required:            true,
+				MarkdownDescription: "The Kubernetes version the cluster is currently running.",
+			},
+			"to_version": {
+				Type:                types.StringType,
+				Required:            true,
+				MarkdownDescription: "The Kubernetes version to upgrade the cluster to. Must be within one minor version of `from_version`.",
+			},
+			"control_nodes": {
+				Type:                types.ListType{ElemType: types.StringType},
+				Required:            true,
+				MarkdownDescription: "IP addresses of the cluster's control plane nodes.",
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateIP(),
+				},
+			},
+			"worker_nodes": {
+				Type:                types.ListType{ElemType: types.StringType},
+				Optional:            true,
+				MarkdownDescription: "IP addresses of the cluster's worker nodes.",
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateIP(),
+				},
+			},
+			"base_config": {
+				Type:                types.StringType,
+				Required:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The base config from the cluster's talos_configuration.",
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateBaseConfig(),
+				},
+			},
+			"drift": {
+				Type:     types.MapType{ElemType: types.StringType},
+				Computed: true,
+				MarkdownDescription: "The versions of the components which were read running another version than `to_version`, keyed by " +
+					"`<node>/<component>`. Components which drifted are upgraded again on the next apply.",
+			},
+			"id": {
+				Computed:            true,
+				MarkdownDescription: "Identifier, derived from the target Kubernetes version.",
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					tfsdk.UseStateForUnknown(),
+				},
+				Type: types.StringType,
+			},
+		},
+	}, nil
+}
+
+type talosKubernetesUpgradeResourceData struct {
+	FromVersion  types.String   `tfsdk:"from_version"`
+	ToVersion    types.String   `tfsdk:"to_version"`
+	ControlNodes []types.String `tfsdk:"control_nodes"`
+	WorkerNodes  []types.String `tfsdk:"worker_nodes"`
+	BaseConfig   types.String   `tfsdk:"base_config"`
+	Drift        types.Map      `tfsdk:"drift"`
+	ID           types.String   `tfsdk:"id"`
+}
+
+// noDrift is the drift of a cluster running the target version on every node.
+var noDrift = types.Map{ElemType: types.StringType, Elems: map[string]attr.Value{}}
+
+// kubernetesVersion is a parsed Kubernetes release version.
+type kubernetesVersion struct {
+	Major, Minor, Patch int
+}
+
+func parseKubernetesVersion(in string) (v kubernetesVersion, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(in, "v"), ".", 3)
+	if len(parts) != 3 {
+		return v, fmt.Errorf("version %q is not of the form major.minor.patch", in)
+	}
+
+	// Drop any pre-release or build suffix from the patch component.
+	if i := strings.IndexAny(parts[2], "-+"); i >= 0 {
+		parts[2] = parts[2][:i]
+	}
+
+	nums := make([]int, 3)
+	for i, part := range parts {
+		if nums[i], err = strconv.Atoi(part); err != nil {
+			return v, fmt.Errorf("version %q has an invalid component %q: %w", in, part, err)
+		}
+	}
+
+	return kubernetesVersion{Major: nums[0], Minor: nums[1], Patch: nums[2]}, nil
+}
+
+func (v kubernetesVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// validateKubernetesSkew ensures an upgrade path is supported by Kubernetes. Only a single minor version may be
+// crossed at a time and the control plane may never be downgraded across a minor version.
+func validateKubernetesSkew(from, to kubernetesVersion) error {
+	if from.Major != to.Major {
+		return fmt.Errorf("upgrades between major versions are unsupported (%s -> %s)", from, to)
+	}
+
+	if to.Minor < from.Minor {
+		return fmt.Errorf("downgrading across minor versions is unsupported (%s -> %s)", from, to)
+	}
+
+	if to.Minor-from.Minor > 1 {
+		return fmt.Errorf("upgrades may only cross a single minor version at a time (%s -> %s)", from, to)
+	}
+
+	return nil
+}
+
+// imageVersion returns the version tag of a container image reference. The digest of an image pinned by digest is
+// ignored, an image pinned only by digest has no version.
+func imageVersion(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+
+	if i := strings.LastIndex(image, ":"); i >= 0 && !strings.Contains(image[i:], "/") {
+		return strings.TrimPrefix(image[i+1:], "v")
+	}
+
+	return ""
+}
+
+// kubernetesComponent describes a single Kubernetes component managed through the Talos machine configuration.
+type kubernetesComponent struct {
+	// Name of the component. For static pods this is also the prefix of the pod's name.
+	Name string
+	// Image is the upstream image repository the component is pulled from.
+	Image string
+	// Get returns the component's image from a Talos machine configuration.
+	Get func(*v1alpha1.Config) string
+	// Set updates the component's image in a Talos machine configuration.
+	Set func(*v1alpha1.Config, string)
+	// StaticPod is true if the component runs as a static pod whose status can be observed.
+	StaticPod bool
+	// DaemonSet is true if the component runs as a DaemonSet in kube-system, whose rollout can be observed once
+	// every control node renders the new image.
+	DaemonSet bool
+}
+
+var (
+	controlPlaneComponents = []kubernetesComponent{
+		{
+			Name:  "kube-apiserver",
+			Image: constants.KubernetesAPIServerImage,
+			Get: func(cfg *v1alpha1.Config) string {
+				return cfg.Cluster().APIServer().Image()
+			},
+			Set: func(cfg *v1alpha1.Config, image string) {
+				if cfg.ClusterConfig.APIServerConfig == nil {
+					cfg.ClusterConfig.APIServerConfig = &v1alpha1.APIServerConfig{}
+				}
+				cfg.ClusterConfig.APIServerConfig.ContainerImage = image
+			},
+			StaticPod: true,
+		},
+		{
+			Name:  "kube-controller-manager",
+			Image: constants.KubernetesControllerManagerImage,
+			Get: func(cfg *v1alpha1.Config) string {
+				return cfg.Cluster().ControllerManager().Image()
+			},
+			Set: func(cfg *v1alpha1.Config, image string) {
+				if cfg.ClusterConfig.ControllerManagerConfig == nil {
+					cfg.ClusterConfig.ControllerManagerConfig = &v1alpha1.ControllerManagerConfig{}
+				}
+				cfg.ClusterConfig.ControllerManagerConfig.ContainerImage = image
+			},
+			StaticPod: true,
+		},
+		{
+			Name:  "kube-scheduler",
+			Image: constants.KubernetesSchedulerImage,
+			Get: func(cfg *v1alpha1.Config) string {
+				return cfg.Cluster().Scheduler().Image()
+			},
+			Set: func(cfg *v1alpha1.Config, image string) {
+				if cfg.ClusterConfig.SchedulerConfig == nil {
+					cfg.ClusterConfig.SchedulerConfig = &v1alpha1.SchedulerConfig{}
+				}
+				cfg.ClusterConfig.SchedulerConfig.ContainerImage = image
+			},
+			StaticPod: true,
+		},
+		{
+			// kube-proxy runs as a daemonset rendered from the control plane's configuration.
+			Name:  "kube-proxy",
+			Image: constants.KubeProxyImage,
+			Get: func(cfg *v1alpha1.Config) string {
+				return cfg.Cluster().Proxy().Image()
+			},
+			Set: func(cfg *v1alpha1.Config, image string) {
+				if cfg.ClusterConfig.ProxyConfig == nil {
+					cfg.ClusterConfig.ProxyConfig = &v1alpha1.ProxyConfig{}
+				}
+				cfg.ClusterConfig.ProxyConfig.ContainerImage = image
+			},
+			DaemonSet: true,
+		},
+	}
+
+	kubeletComponent = kubernetesComponent{
+		Name:  "kubelet",
+		Image: constants.KubeletImage,
+		Get: func(cfg *v1alpha1.Config) string {
+			return cfg.Machine().Kubelet().Image()
+		},
+		Set: func(cfg *v1alpha1.Config, image string) {
+			if cfg.MachineConfig.MachineKubelet == nil {
+				cfg.MachineConfig.MachineKubelet = &v1alpha1.KubeletConfig{}
+			}
+			cfg.MachineConfig.MachineKubelet.KubeletImage = image
+		},
+	}
+)
+
+// upgradeKubernetes moves every node's Kubernetes components to the plan's target version. Control plane components
+// are upgraded first, one component at a time across all control nodes, followed by the kubelet on every node.
+func (plan *talosKubernetesUpgradeResourceData) upgradeKubernetes(ctx context.Context) (errDesc string, err error) {
+	from, err := parseKubernetesVersion(plan.FromVersion.Value)
+	if err != nil {
+		return "Invalid from_version.", err
+	}
+
+	to, err := parseKubernetesVersion(plan.ToVersion.Value)
+	if err != nil {
+		return "Invalid to_version.", err
+	}
+
+	if err := validateKubernetesSkew(from, to); err != nil {
+		return "Unsupported Kubernetes version skew.", err
+	}
+
+	input := generate.Input{}
+	if err := json.Unmarshal([]byte(plan.BaseConfig.Value), &input); err != nil {
+		return "Unable to marshal base_config data into it's generate.Input struct.", err
+	}
+
+	if len(plan.ControlNodes) < 1 {
+		return "No control nodes.", fmt.Errorf("at least one control node is required to upgrade Kubernetes")
+	}
+
+	// Ensure the cluster is actually running the version the upgrade is expected to start from, or has already
+	// been partially upgraded by a previous run.
+	live, errDesc, err := nodeConfig(ctx, input, plan.ControlNodes[0].Value)
+	if err != nil {
+		return errDesc, err
+	}
+
+	if current := imageVersion(live.Cluster().APIServer().Image()); current != from.String() && current != to.String() {
+		return "Unexpected Kubernetes version.",
+			fmt.Errorf("control plane is running Kubernetes %q, expected %q or %q", current, from, to)
+	}
+
+	for _, component := range controlPlaneComponents {
+		for _, ip := range plan.ControlNodes {
+			if err := upgradeComponent(ctx, input, ip.Value, component, to); err != nil {
+				return fmt.Sprintf("Unable to upgrade %s on %s.", component.Name, ip.Value), err
+			}
+		}
+
+		if component.DaemonSet {
+			if err := waitDaemonSet(ctx, input, component, to); err != nil {
+				return fmt.Sprintf("Unable to upgrade %s.", component.Name), err
+			}
+		}
+	}
+
+	nodes := append(append([]types.String{}, plan.ControlNodes...), plan.WorkerNodes...)
+	for _, ip := range nodes {
+		if err := upgradeComponent(ctx, input, ip.Value, kubeletComponent, to); err != nil {
+			return fmt.Sprintf("Unable to upgrade kubelet on %s.", ip.Value), err
+		}
+	}
+
+	return "", nil
+}
+
+// driftedComponents returns the versions of the components configured in cfg which don't match to, by component name.
+// Components pinned only by digest, without a version tag, are skipped.
+func driftedComponents(cfg *v1alpha1.Config, components []kubernetesComponent, to kubernetesVersion) map[string]kubernetesVersion {
+	drifted := map[string]kubernetesVersion{}
+	for _, component := range components {
+		version, err := parseKubernetesVersion(imageVersion(component.Get(cfg)))
+		if err == nil && version != to {
+			drifted[component.Name] = version
+		}
+	}
+
+	return drifted
+}
+
+// readKubernetes compares the component versions configured on every node with the state's target version. The
+// versions of the components running another version are recorded in drift, so that the next plan upgrades them
+// again.
+func (state *talosKubernetesUpgradeResourceData) readKubernetes(ctx context.Context) (diags diag.Diagnostics) {
+	to, err := parseKubernetesVersion(state.ToVersion.Value)
+	if err != nil {
+		diags.AddError("Invalid to_version.", err.Error())
+		return
+	}
+
+	input := generate.Input{}
+	if err := json.Unmarshal([]byte(state.BaseConfig.Value), &input); err != nil {
+		diags.AddError("Unable to marshal base_config data into it's generate.Input struct.", err.Error())
+		return
+	}
+
+	type node struct {
+		ip         string
+		components []kubernetesComponent
+	}
+
+	nodes := []node{}
+	for _, ip := range state.ControlNodes {
+		nodes = append(nodes, node{ip.Value, append(append([]kubernetesComponent{}, controlPlaneComponents...), kubeletComponent)})
+	}
+	for _, ip := range state.WorkerNodes {
+		nodes = append(nodes, node{ip.Value, []kubernetesComponent{kubeletComponent}})
+	}
+
+	drift := []string{}
+	state.Drift = types.Map{ElemType: types.StringType, Elems: map[string]attr.Value{}}
+	for _, n := range nodes {
+		cfg, errDesc, err := nodeConfig(ctx, input, n.ip)
+		if err != nil {
+			diags.AddWarning("Unable to read the Kubernetes version of "+n.ip+".", errDesc+" "+err.Error())
+			continue
+		}
+
+		drifted := driftedComponents(cfg, n.components, to)
+		for _, component := range n.components {
+			if version, ok := drifted[component.Name]; ok {
+				drift = append(drift, fmt.Sprintf("%s on %s runs %s", component.Name, n.ip, version))
+				state.Drift.Elems[n.ip+"/"+component.Name] = types.String{Value: version.String()}
+			}
+		}
+	}
+
+	if len(drift) > 0 {
+		tflog.Warn(ctx, "Kubernetes components have drifted from "+to.String()+": "+strings.Join(drift, ", "))
+	}
+
+	return
+}
+
+// nodeConfig retrieves the live machine configuration of a node.
+func nodeConfig(ctx context.Context, input generate.Input, ip string) (*v1alpha1.Config, string, error) {
+	conn, err := secureConn(ctx, input, net.JoinHostPort(ip, strconv.Itoa(talosPort)))
+	if err != nil {
+		return nil, "Unable to make a secure connection to read the node's Talos config.", err
+	}
+	defer conn.Close()
+
+	return fetchConfig(ctx, conn)
+}
+
+// upgradeComponent patches a single component's image on a single node and waits for it to become healthy.
+// Nodes that already run the target image are left untouched so that an interrupted upgrade can be resumed.
+func upgradeComponent(ctx context.Context, input generate.Input, ip string, component kubernetesComponent, to kubernetesVersion) error {
+	image := fmt.Sprintf("%s:v%s", component.Image, to)
+
+	conn, err := secureConn(ctx, input, net.JoinHostPort(ip, strconv.Itoa(talosPort)))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	cfg, _, err := fetchConfig(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	patched := &v1alpha1.Config{}
+	cfg.DeepCopyInto(patched)
+	component.Set(patched, image)
+
+	before, err := cfg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	after, err := patched.Bytes()
+	if err != nil {
+		return err
+	}
+
+	if string(before) != string(after) {
+		tflog.Info(ctx, "Updating "+component.Name+" on "+ip+" to "+image)
+
+		client := machine.NewMachineServiceClient(conn)
+		if _, err := client.ApplyConfiguration(ctx, &machine.ApplyConfigurationRequest{
+			Data: after,
+			Mode: machine.ApplyConfigurationRequest_NO_REBOOT,
+		}); err != nil {
+			return fmt.Errorf("error applying patched configuration: %w", err)
+		}
+	}
+
+	// The DaemonSet is rendered by the control plane as a whole, so its rollout is only awaited once every control
+	// node has been patched.
+	if component.DaemonSet {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, componentHealthTimeout)
+	defer cancel()
+
+	for {
+		var err error
+		switch {
+		case component.StaticPod:
+			err = staticPodReady(ctx, resource.NewResourceServiceClient(conn), component.Name, image)
+		case component.Name == kubeletComponent.Name:
+			err = serviceHealthy(ctx, machine.NewMachineServiceClient(conn), component.Name)
+		default:
+			return fmt.Errorf("unable to observe the health of %s", component.Name)
+		}
+
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for %s to become healthy: %w", component.Name, err)
+		default:
+			tflog.Info(ctx, "Waiting for "+component.Name+" on "+ip+" reason "+err.Error())
+			time.Sleep(componentPollInterval)
+		}
+	}
+}
+
+// waitDaemonSet waits for the component's DaemonSet in kube-system to roll out the target version.
+func waitDaemonSet(ctx context.Context, input generate.Input, component kubernetesComponent, to kubernetesVersion) error {
+	image := fmt.Sprintf("%s:v%s", component.Image, to)
+
+	k8s, err := newKubernetesClient(input)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, componentHealthTimeout)
+	defer cancel()
+
+	for {
+		err := k8s.daemonSetRolledOut(ctx, "kube-system", component.Name, image)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for %s to roll out: %w", component.Name, err)
+		default:
+			tflog.Info(ctx, "Waiting for "+component.Name+" to roll out, reason "+err.Error())
+			time.Sleep(componentPollInterval)
+		}
+	}
+}
+
+// staticPodStatus is the subset of a Kubernetes PodStatus needed to determine whether a static pod is healthy.
+type staticPodStatus struct {
+	Conditions []struct {
+		Type   string `yaml:"type"`
+		Status string `yaml:"status"`
+	} `yaml:"conditions"`
+	ContainerStatuses []struct {
+		Image string `yaml:"image"`
+		Ready bool   `yaml:"ready"`
+	} `yaml:"containerStatuses"`
+}
+
+// staticPodReady returns nil once the node reports the named static pod as ready and running the expected image.
+func staticPodReady(ctx context.Context, client resource.ResourceServiceClient, name string, image string) error {
+	stream, err := client.List(ctx, &resource.ListRequest{
+		Namespace: "k8s",
+		Type:      "StaticPodStatuses.kubernetes.talos.dev",
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return fmt.Errorf("static pod %s not found", name)
+		}
+		if err != nil {
+			return err
+		}
+
+		if msg.Resource == nil || !strings.HasPrefix(msg.Resource.Metadata.Id, "kube-system/"+name+"-") {
+			continue
+		}
+
+		status := staticPodStatus{}
+		if err := yaml.Unmarshal(msg.Resource.Spec.Yaml, &status); err != nil {
+			return fmt.Errorf("unable to unmarshal static pod status: %w", err)
+		}
+
+		for _, container := range status.ContainerStatuses {
+			if container.Image != image {
+				return fmt.Errorf("static pod %s is running image %s", name, container.Image)
+			}
+			if !container.Ready {
+				return fmt.Errorf("static pod %s container is not ready", name)
+			}
+		}
+
+		for _, condition := range status.Conditions {
+			if condition.Type == "Ready" && condition.Status == "True" {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("static pod %s is not ready", name)
+	}
+}
+
+// serviceHealthy returns nil once the node reports the named Talos service as running and healthy.
+func serviceHealthy(ctx context.Context, client machine.MachineServiceClient, id string) error {
+	resp, err := client.ServiceList(ctx, &emptypb.Empty{})
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range resp.Messages {
+		for _, svc := range msg.Services {
+			if svc.Id != id {
+				continue
+			}
+
+			if svc.State != "Running" || svc.Health == nil || !svc.Health.Healthy {
+				return fmt.Errorf("service %s is %s", id, strings.ToLower(svc.State))
+			}
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("service %s not found", id)
+}
+
+func (t talosKubernetesUpgradeResourceType) NewResource(ctx context.Context, in tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	provider, diags := convertProviderType(in)
+	return talosKubernetesUpgradeResource{
+		provider: provider,
+	}, diags
+}
+
+type talosKubernetesUpgradeResource struct {
+	provider provider
+}
+
+func (r talosKubernetesUpgradeResource) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	var (
+		plan talosKubernetesUpgradeResourceData
+	)
+
+	if !r.provider.configured {
+		resp.Diagnostics.AddError("Provider not configured.", "The Talos Kubernetes upgrade's Create method has been called without the provider being configured. This is a provider bug.")
+		return
+	}
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if errDesc, err := plan.upgradeKubernetes(ctx); err != nil {
+		resp.Diagnostics.AddError(errDesc, err.Error())
+		return
+	}
+
+	plan.Drift = noDrift
+	plan.ID = types.String{Value: plan.ToVersion.Value}
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r talosKubernetesUpgradeResource) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	var (
+		state talosKubernetesUpgradeResourceData
+	)
+
+	if !r.provider.configured {
+		resp.Diagnostics.AddError("Provider not configured.", "The Talos Kubernetes upgrade's Read method has been called without the provider being configured. This is a provider bug.")
+		return
+	}
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(state.readKubernetes(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r talosKubernetesUpgradeResource) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	var (
+		plan talosKubernetesUpgradeResourceData
+	)
+
+	if !r.provider.configured {
+		resp.Diagnostics.AddError("Provider not configured.", "The Talos Kubernetes upgrade's Update method has been called without the provider being configured. This is a provider bug.")
+		return
+	}
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if errDesc, err := plan.upgradeKubernetes(ctx); err != nil {
+		resp.Diagnostics.AddError(errDesc, err.Error())
+		return
+	}
+
+	plan.Drift = noDrift
+	plan.ID = types.String{Value: plan.ToVersion.Value}
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete only removes the upgrade from state. The cluster is left running the version it was upgraded to.
+func (r talosKubernetesUpgradeResource) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	if !r.provider.configured {
+		resp.Diagnostics.AddError("Provider not configured.", "The Talos Kubernetes upgrade's Delete method has been called without the provider being configured. This is a provider bug.")
+		return
+	}
+}
+
+// ModifyPlan plans an upgrade of the components which were read running another version than to_version, even if
+// the configuration didn't change.
+func (r talosKubernetesUpgradeResource) ModifyPlan(ctx context.Context, req tfsdk.ModifyResourcePlanRequest, resp *tfsdk.ModifyResourcePlanResponse) {
+	var drift types.Map
+
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("drift"), &drift)...)
+	if resp.Diagnostics.HasError() || len(drift.Elems) == 0 {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("drift"), noDrift)...)
+}
+
+func (r talosKubernetesUpgradeResource) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
+	tfsdk.ResourceImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
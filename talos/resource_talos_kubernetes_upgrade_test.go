@@ -0,0 +1,91 @@
+package talos
+
+import (
+	"reflect"
+	"testing"
+
+	v1alpha1 "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+)
+
+func TestValidateKubernetesSkew(t *testing.T) {
+	tests := []struct {
+		from, to string
+		valid    bool
+	}{
+		{"1.24.2", "1.24.3", true},
+		{"1.24.2", "v1.25.0", true},
+		{"1.24.2", "1.24.2", true},
+		{"1.24.3", "1.24.2", true},
+		{"1.24.2", "1.26.0", false},
+		{"1.24.2", "1.23.6", false},
+		{"1.24.2", "2.24.2", false},
+	}
+
+	for _, tt := range tests {
+		from, err := parseKubernetesVersion(tt.from)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		to, err := parseKubernetesVersion(tt.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := validateKubernetesSkew(from, to); (err == nil) != tt.valid {
+			t.Errorf("validateKubernetesSkew(%s, %s): expected valid %t, got error %v", tt.from, tt.to, tt.valid, err)
+		}
+	}
+}
+
+func TestParseKubernetesVersion(t *testing.T) {
+	for _, in := range []string{"1.24", "a.b.c", "", "1.24.x"} {
+		if _, err := parseKubernetesVersion(in); err == nil {
+			t.Errorf("expected error parsing %q", in)
+		}
+	}
+
+	if v, err := parseKubernetesVersion("v1.25.0-rc.1"); err != nil || v.String() != "1.25.0" {
+		t.Errorf("unexpected result parsing v1.25.0-rc.1: %v %v", v, err)
+	}
+
+	for image, expected := range map[string]string{
+		"k8s.gcr.io/kube-apiserver:v1.24.2":                            "1.24.2",
+		"registry.local:5000/kube-apiserver:v1.24.2@sha256:0123456789": "1.24.2",
+		"k8s.gcr.io/kube-apiserver@sha256:0123456789abcdef":            "",
+		"registry.local:5000/kube-apiserver":                           "",
+	} {
+		if v := imageVersion(image); v != expected {
+			t.Errorf("imageVersion(%q): expected %q, got %q", image, expected, v)
+		}
+	}
+}
+
+// TestDriftedComponents checks that components configured with a version other than the target are reported.
+func TestDriftedComponents(t *testing.T) {
+	cfg := &v1alpha1.Config{
+		MachineConfig: &v1alpha1.MachineConfig{},
+		ClusterConfig: &v1alpha1.ClusterConfig{},
+	}
+
+	components := append(append([]kubernetesComponent{}, controlPlaneComponents...), kubeletComponent)
+	for _, component := range components {
+		component.Set(cfg, component.Image+":v1.24.3")
+	}
+	kubeletComponent.Set(cfg, kubeletComponent.Image+":v1.24.2")
+	controlPlaneComponents[0].Set(cfg, controlPlaneComponents[0].Image+"@sha256:0123456789abcdef")
+	controlPlaneComponents[1].Set(cfg, controlPlaneComponents[1].Image+":v1.24.2@sha256:0123456789abcdef")
+
+	to, err := parseKubernetesVersion("1.24.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]kubernetesVersion{
+		kubeletComponent.Name:          {Major: 1, Minor: 24, Patch: 2},
+		controlPlaneComponents[1].Name: {Major: 1, Minor: 24, Patch: 2},
+	}
+	if drifted := driftedComponents(cfg, components, to); !reflect.DeepEqual(drifted, expected) {
+		t.Errorf("expected drifted components %v, got %v", expected, drifted)
+	}
+}
@@ -0,0 +1,291 @@
+package talos
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"github.com/talos-systems/crypto/x509"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/config/configloader"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ tfsdk.ResourceType = talosMachineConfigurationApplyResourceType{}
+var _ tfsdk.Resource = talosMachineConfigurationApplyResource{}
+var _ tfsdk.ResourceWithImportState = talosMachineConfigurationApplyResource{}
+var _ tfsdk.ResourceWithValidateConfig = talosMachineConfigurationApplyResource{}
+
+type talosMachineConfigurationApplyResourceType struct{}
+
+func (t talosMachineConfigurationApplyResourceType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Applies a machine configuration rendered outside of the provider, for example by `talosctl gen config`, to a Talos node " +
+			"and tracks it for drift. Destroying the resource leaves the node running with its configuration.",
+		Attributes: map[string]tfsdk.Attribute{
+			"endpoint": {
+				Type:                types.StringType,
+				Required:            true,
+				MarkdownDescription: "Address of the node's Talos API. The maintenance API at this address is used to apply the initial configuration.",
+			},
+			"machine_configuration": {
+				Type:                types.StringType,
+				Required:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The machine configuration YAML to apply.",
+			},
+			"ca_certificate": {
+				Type:                types.StringType,
+				Required:            true,
+				MarkdownDescription: "PEM encoded Talos API CA certificate, used to verify the node once it is configured.",
+			},
+			"client_certificate": {
+				Type:                types.StringType,
+				Required:            true,
+				MarkdownDescription: "PEM encoded client certificate used to authenticate with the Talos API.",
+			},
+			"client_key": {
+				Type:                types.StringType,
+				Required:            true,
+				Sensitive:           true,
+				MarkdownDescription: "PEM encoded private key of the client certificate.",
+			},
+			"on_unreachable": onUnreachableAttribute,
+			"apply_mode":     applyModeAttributes["apply_mode"],
+			"try_timeout":    applyModeAttributes["try_timeout"],
+			"applied_mode":   applyModeAttributes["applied_mode"],
+			"rebooted":       applyModeAttributes["rebooted"],
+			"id": {
+				Computed:            true,
+				MarkdownDescription: "Identifier, derived from the node's endpoint.",
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					tfsdk.UseStateForUnknown(),
+				},
+				Type: types.StringType,
+			},
+		},
+	}, nil
+}
+
+type talosMachineConfigurationApplyResourceData struct {
+	Endpoint             types.String `tfsdk:"endpoint"`
+	MachineConfiguration types.String `tfsdk:"machine_configuration"`
+	CACertificate        types.String `tfsdk:"ca_certificate"`
+	ClientCertificate    types.String `tfsdk:"client_certificate"`
+	ClientKey            types.String `tfsdk:"client_key"`
+	OnUnreachable        types.String `tfsdk:"on_unreachable"`
+	ApplyMode            types.String `tfsdk:"apply_mode"`
+	TryTimeout           types.String `tfsdk:"try_timeout"`
+	AppliedMode          types.String `tfsdk:"applied_mode"`
+	Rebooted             types.Bool   `tfsdk:"rebooted"`
+	ID                   types.String `tfsdk:"id"`
+}
+
+// input builds an input bundle holding only the client credentials, so the secure connection helpers can be used.
+func (plan *talosMachineConfigurationApplyResourceData) input() generate.Input {
+	return generate.Input{
+		Certs: &generate.Certs{
+			Admin: &x509.PEMEncodedCertificateAndKey{
+				Crt: []byte(plan.ClientCertificate.Value),
+				Key: []byte(plan.ClientKey.Value),
+			},
+			OS: &x509.PEMEncodedCertificateAndKey{
+				Crt: []byte(plan.CACertificate.Value),
+			},
+		},
+	}
+}
+
+func (plan *talosMachineConfigurationApplyResourceData) host() string {
+	return net.JoinHostPort(plan.Endpoint.Value, strconv.Itoa(talosPort))
+}
+
+func (t talosMachineConfigurationApplyResourceType) NewResource(ctx context.Context, in tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	provider, diags := convertProviderType(in)
+	return talosMachineConfigurationApplyResource{
+		provider: provider,
+	}, diags
+}
+
+type talosMachineConfigurationApplyResource struct {
+	provider provider
+}
+
+func (r talosMachineConfigurationApplyResource) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	var (
+		plan talosMachineConfigurationApplyResourceData
+	)
+
+	if !r.provider.configured {
+		resp.Diagnostics.AddError("Provider not configured.", "The Talos machine configuration apply's Create method has been called without the provider being configured. This is a provider bug.")
+		return
+	}
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if _, err := configloader.NewFromBytes([]byte(plan.MachineConfiguration.Value)); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("machine_configuration"), "Invalid machine configuration.", err.Error())
+		return
+	}
+
+	applyReq, err := applyRequest([]byte(plan.MachineConfiguration.Value), plan.ApplyMode, plan.TryTimeout, machine.ApplyConfigurationRequest_REBOOT)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid apply mode.", err.Error())
+		return
+	}
+
+	if warning := maintenanceRequest(applyReq); warning != "" {
+		resp.Diagnostics.AddWarning("Apply mode not supported during provisioning.", warning)
+	}
+
+	conn, err := insecureConn(ctx, plan.host())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to make insecure connection to Talos machine.", err.Error())
+		return
+	}
+
+	applied, err := applyConfigRequest(ctx, conn, applyReq)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to apply node configuration yaml", err.Error())
+		return
+	}
+	plan.AppliedMode, plan.Rebooted = appliedResult(applied)
+
+	for _, warning := range applied.Warnings {
+		resp.Diagnostics.AddWarning("Talos configuration warning.", warning)
+	}
+
+	plan.ID = types.String{Value: plan.Endpoint.Value}
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read compares the live configuration of the node against state. If they differ the live configuration is stored,
+// so that the difference shows up as a change in the next plan.
+func (r talosMachineConfigurationApplyResource) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	var (
+		state talosMachineConfigurationApplyResourceData
+	)
+
+	if !r.provider.configured {
+		resp.Diagnostics.AddError("Provider not configured.", "The Talos machine configuration apply's Read method has been called without the provider being configured. This is a provider bug.")
+		return
+	}
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if unreachable(ctx, state.OnUnreachable, state.input(), state.Endpoint.Value, resp) {
+		return
+	}
+
+	conn, err := secureConn(ctx, state.input(), state.host())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to make a secure connection to read the node's Talos config.", err.Error())
+		return
+	}
+	defer conn.Close()
+
+	live, errDesc, err := fetchConfigBytes(ctx, conn)
+	if err != nil {
+		resp.Diagnostics.AddError(errDesc, err.Error())
+		return
+	}
+
+	equal, err := configEqual([]byte(state.MachineConfiguration.Value), live)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to compare the node's Talos config.", err.Error())
+		return
+	}
+
+	if !equal {
+		state.MachineConfiguration = types.String{Value: string(live)}
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r talosMachineConfigurationApplyResource) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	var (
+		plan talosMachineConfigurationApplyResourceData
+	)
+
+	if !r.provider.configured {
+		resp.Diagnostics.AddError("Provider not configured.", "The Talos machine configuration apply's Update method has been called without the provider being configured. This is a provider bug.")
+		return
+	}
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if _, err := configloader.NewFromBytes([]byte(plan.MachineConfiguration.Value)); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("machine_configuration"), "Invalid machine configuration.", err.Error())
+		return
+	}
+
+	applyReq, err := applyRequest([]byte(plan.MachineConfiguration.Value), plan.ApplyMode, plan.TryTimeout, machine.ApplyConfigurationRequest_AUTO)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid apply mode.", err.Error())
+		return
+	}
+
+	conn, err := secureConn(ctx, plan.input(), plan.host())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to make secure connection to Talos machine.", err.Error())
+		return
+	}
+
+	applied, err := applyConfigRequest(ctx, conn, applyReq)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to apply node configuration yaml", err.Error())
+		return
+	}
+	plan.AppliedMode, plan.Rebooted = appliedResult(applied)
+
+	for _, warning := range applied.Warnings {
+		resp.Diagnostics.AddWarning("Talos configuration warning.", warning)
+	}
+
+	plan.ID = types.String{Value: plan.Endpoint.Value}
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete only removes the resource from state, the node keeps running with its last applied configuration.
+func (r talosMachineConfigurationApplyResource) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	if !r.provider.configured {
+		resp.Diagnostics.AddError("Provider not configured.", "The Talos machine configuration apply's Delete method has been called without the provider being configured. This is a provider bug.")
+	}
+}
+
+func (r talosMachineConfigurationApplyResource) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var mode, tryTimeout types.String
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("apply_mode"), &mode)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("try_timeout"), &tryTimeout)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateApplyMode(mode, tryTimeout, path.Empty())...)
+}
+
+func (r talosMachineConfigurationApplyResource) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
+	tfsdk.ResourceImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
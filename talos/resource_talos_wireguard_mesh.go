@@ -0,0 +1,352 @@
+package talos
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-talos/talos/datatypes"
+)
+
+var _ tfsdk.ResourceType = talosWireguardMeshResourceType{}
+var _ tfsdk.Resource = talosWireguardMeshResource{}
+var _ tfsdk.ResourceWithModifyPlan = talosWireguardMeshResource{}
+var _ tfsdk.ResourceWithValidateConfig = talosWireguardMeshResource{}
+
+// defaultWireguardPort is the port members listen on when the mesh doesn't configure one.
+const defaultWireguardPort = 51820
+
+type talosWireguardMeshResourceType struct{}
+
+func (t talosWireguardMeshResourceType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Generates a full mesh Wireguard overlay for a set of nodes. A key pair is generated and kept for each member, and " +
+			"each member's `wireguard` block lists every other member as a peer, ready to be used in a node's network device. " +
+			"Nothing is applied to the nodes by the resource itself.",
+		Attributes: map[string]tfsdk.Attribute{
+			"members": {
+				Required:            true,
+				MarkdownDescription: "The nodes of the mesh.",
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"name": {
+						Type:                types.StringType,
+						Required:            true,
+						MarkdownDescription: "Name of the member, the key of its `wireguard` block.",
+					},
+					"endpoint": {
+						Type:     types.StringType,
+						Optional: true,
+						MarkdownDescription: "Address and port other members connect to, it should use the mesh's `listen_port`. Members without an " +
+							"endpoint, such as nodes behind NAT, only connect to the others.",
+						Validators: []tfsdk.AttributeValidator{
+							datatypes.ValidateEndpoint(),
+						},
+					},
+					"address": {
+						Type:                types.StringType,
+						Required:            true,
+						MarkdownDescription: "The member's address within the tunnel, in CIDR notation. Assign it to the member's Wireguard device.",
+						Validators: []tfsdk.AttributeValidator{
+							datatypes.ValidateCIDR(),
+						},
+					},
+					"allowed_ips": {
+						Type: types.ListType{
+							ElemType: types.StringType,
+						},
+						Optional:            true,
+						MarkdownDescription: "Further subnets routed through the member, in addition to its tunnel address.",
+						Validators: []tfsdk.AttributeValidator{
+							datatypes.ValidateCIDR(),
+						},
+					},
+					"private_key": {
+						Type:                types.StringType,
+						Optional:            true,
+						Computed:            true,
+						Sensitive:           true,
+						MarkdownDescription: "The member's private key (base64 encoded). Generated if not provided, and kept for as long as the member is part of the mesh.",
+						Validators: []tfsdk.AttributeValidator{
+							datatypes.ValidateWireguardKey(),
+						},
+					},
+					"public_key": {
+						Type:                types.StringType,
+						Computed:            true,
+						MarkdownDescription: "Derived from the member's private key.",
+					},
+				}),
+			},
+			"listen_port": {
+				Type:                types.Int64Type,
+				Optional:            true,
+				MarkdownDescription: fmt.Sprintf("Port the members listen on. Defaults to `%d`.", defaultWireguardPort),
+			},
+			"persistent_keepalive_interval": {
+				Type:                types.Int64Type,
+				Optional:            true,
+				MarkdownDescription: "Persistent keepalive interval of the peers, in seconds. Keeps connections of members behind NAT open.",
+			},
+			"wireguard": {
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The `wireguard` block of each member's network device, by member name.",
+				Attributes: tfsdk.MapNestedAttributes(map[string]tfsdk.Attribute{
+					"peers": {
+						Computed:    true,
+						Description: datatypes.WireguardPeerSchema.Description,
+						Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+							"allowed_ips":                   {Type: types.ListType{ElemType: types.StringType}, Computed: true},
+							"endpoint":                      {Type: types.StringType, Computed: true},
+							"persistent_keepalive_interval": {Type: types.Int64Type, Computed: true},
+							"public_key":                    {Type: types.StringType, Computed: true},
+						}),
+					},
+					"firewall_mark": {Type: types.Int64Type, Computed: true},
+					"listen_port":   {Type: types.Int64Type, Computed: true},
+					"public_key":    {Type: types.StringType, Computed: true},
+					"private_key":   {Type: types.StringType, Computed: true, Sensitive: true},
+				}),
+			},
+			"id": {
+				Computed:            true,
+				MarkdownDescription: "Identifier, derived from the names of the members.",
+				Type:                types.StringType,
+			},
+		},
+	}, nil
+}
+
+func (t talosWireguardMeshResourceType) NewResource(ctx context.Context, in tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	provider, diags := convertProviderType(in)
+	return talosWireguardMeshResource{
+		provider: provider,
+	}, diags
+}
+
+type talosWireguardMeshResourceData struct {
+	Members                     []wireguardMeshMember          `tfsdk:"members"`
+	ListenPort                  types.Int64                    `tfsdk:"listen_port"`
+	PersistentKeepaliveInterval types.Int64                    `tfsdk:"persistent_keepalive_interval"`
+	Wireguard                   map[string]datatypes.Wireguard `tfsdk:"wireguard"`
+	ID                          types.String                   `tfsdk:"id"`
+}
+
+type wireguardMeshMember struct {
+	Name       types.String   `tfsdk:"name"`
+	Endpoint   types.String   `tfsdk:"endpoint"`
+	Address    types.String   `tfsdk:"address"`
+	AllowedIPs []types.String `tfsdk:"allowed_ips"`
+	PrivateKey types.String   `tfsdk:"private_key"`
+	PublicKey  types.String   `tfsdk:"public_key"`
+}
+
+// generate fills in the members' key pairs and builds their Wireguard blocks. Private keys which aren't configured are
+// taken from the first of priors holding a key for the member, matched by name, and generated otherwise.
+func (plan *talosWireguardMeshResourceData) generate(priors ...[]wireguardMeshMember) error {
+	names := make([]string, len(plan.Members))
+
+	for i := range plan.Members {
+		member := &plan.Members[i]
+		names[i] = member.Name.Value
+
+		wireguard := &datatypes.Wireguard{PrivateKey: member.PrivateKey}
+		if err := wireguard.GenerateKeys(priorMemberKey(member.Name.Value, priors)); err != nil {
+			return fmt.Errorf("unable to generate the key pair of member %s: %w", member.Name.Value, err)
+		}
+		member.PrivateKey, member.PublicKey = wireguard.PrivateKey, wireguard.PublicKey
+	}
+
+	listenPort := types.Int64{Value: defaultWireguardPort}
+	if !plan.ListenPort.Null {
+		listenPort = plan.ListenPort
+	}
+
+	plan.Wireguard = map[string]datatypes.Wireguard{}
+	for i, member := range plan.Members {
+		peers := []datatypes.WireguardPeer{}
+
+		for j, other := range plan.Members {
+			if i == j {
+				continue
+			}
+
+			address, err := netip.ParsePrefix(other.Address.Value)
+			if err != nil {
+				return fmt.Errorf("invalid address of member %s: %w", other.Name.Value, err)
+			}
+
+			peers = append(peers, datatypes.WireguardPeer{
+				PublicKey:                   other.PublicKey,
+				Endpoint:                    other.Endpoint,
+				AllowedIPs:                  append([]types.String{{Value: netip.PrefixFrom(address.Addr(), address.Addr().BitLen()).String()}}, other.AllowedIPs...),
+				PersistentKeepaliveInterval: types.Int64{Null: plan.PersistentKeepaliveInterval.Null, Value: plan.PersistentKeepaliveInterval.Value},
+			})
+		}
+
+		plan.Wireguard[member.Name.Value] = datatypes.Wireguard{
+			Peers:        peers,
+			FirewallMark: types.Int64{Null: true},
+			ListenPort:   listenPort,
+			PublicKey:    member.PublicKey,
+			PrivateKey:   member.PrivateKey,
+		}
+	}
+
+	plan.ID = types.String{Value: strings.Join(names, ",")}
+
+	return nil
+}
+
+// priorMemberKey returns the key of a member from the first of priors which holds one.
+func priorMemberKey(name string, priors [][]wireguardMeshMember) *datatypes.Wireguard {
+	for _, prior := range priors {
+		for _, member := range prior {
+			if member.Name.Value == name && !member.PrivateKey.Null && !member.PrivateKey.Unknown {
+				return &datatypes.Wireguard{PrivateKey: member.PrivateKey}
+			}
+		}
+	}
+
+	return nil
+}
+
+type talosWireguardMeshResource struct {
+	provider provider
+}
+
+// ValidateConfig checks that members are unique by name and tunnel address.
+func (r talosWireguardMeshResource) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var members []wireguardMeshMember
+	if diags := req.Config.GetAttribute(ctx, path.Root("members"), &members); diags.HasError() {
+		return
+	}
+
+	names, addresses := map[string]int{}, map[netip.Addr]int{}
+	for i, member := range members {
+		memberPath := path.Root("members").AtListIndex(i)
+
+		if !member.Name.Null && !member.Name.Unknown {
+			if other, ok := names[member.Name.Value]; ok {
+				resp.Diagnostics.AddAttributeError(memberPath.AtName("name"), "Duplicate mesh member.",
+					fmt.Sprintf("Member %d is already named %s.", other, member.Name.Value))
+			}
+			names[member.Name.Value] = i
+		}
+
+		if member.Address.Null || member.Address.Unknown {
+			continue
+		}
+		address, err := netip.ParsePrefix(member.Address.Value)
+		if err != nil {
+			continue
+		}
+		if other, ok := addresses[address.Addr()]; ok {
+			resp.Diagnostics.AddAttributeError(memberPath.AtName("address"), "Duplicate mesh address.",
+				fmt.Sprintf("Member %d already uses the tunnel address %s.", other, address.Addr()))
+		}
+		addresses[address.Addr()] = i
+	}
+}
+
+// ModifyPlan generates the members' keys and Wireguard blocks at plan time, so that they can be used in the
+// configuration of nodes planned alongside the mesh.
+func (r talosWireguardMeshResource) ModifyPlan(ctx context.Context, req tfsdk.ModifyResourcePlanRequest, resp *tfsdk.ModifyResourcePlanResponse) {
+	var (
+		plan  talosWireguardMeshResourceData
+		prior []wireguardMeshMember
+	)
+
+	// Nothing to plan when the mesh is being destroyed. Members which aren't known yet are generated once they are.
+	if req.Plan.Raw.IsNull() || !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+
+	if diags := req.Config.Get(ctx, &plan); diags.HasError() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		if diags := req.State.GetAttribute(ctx, path.Root("members"), &prior); diags.HasError() {
+			return
+		}
+	}
+
+	if err := plan.generate(prior); err != nil {
+		resp.Diagnostics.AddError("Unable to generate the Wireguard mesh.", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r talosWireguardMeshResource) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	var (
+		data    talosWireguardMeshResourceData
+		planned []wireguardMeshMember
+	)
+
+	if !r.provider.configured {
+		resp.Diagnostics.AddError("Provider not configured.", "The Talos Wireguard mesh's Create method has been called without the provider being configured. This is a provider bug.")
+		return
+	}
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("members"), &planned)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := data.generate(planned); err != nil {
+		resp.Diagnostics.AddError("Unable to generate the Wireguard mesh.", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read keeps the mesh as is, it only exists in state.
+func (r talosWireguardMeshResource) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	if !r.provider.configured {
+		resp.Diagnostics.AddError("Provider not configured.", "The Talos Wireguard mesh's Read method has been called without the provider being configured. This is a provider bug.")
+	}
+}
+
+func (r talosWireguardMeshResource) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	var (
+		data           talosWireguardMeshResourceData
+		planned, prior []wireguardMeshMember
+	)
+
+	if !r.provider.configured {
+		resp.Diagnostics.AddError("Provider not configured.", "The Talos Wireguard mesh's Update method has been called without the provider being configured. This is a provider bug.")
+		return
+	}
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("members"), &planned)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("members"), &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := data.generate(planned, prior); err != nil {
+		resp.Diagnostics.AddError("Unable to generate the Wireguard mesh.", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete only removes the mesh from state, the nodes keep their Wireguard devices until their configuration changes.
+func (r talosWireguardMeshResource) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	if !r.provider.configured {
+		resp.Diagnostics.AddError("Provider not configured.", "The Talos Wireguard mesh's Delete method has been called without the provider being configured. This is a provider bug.")
+	}
+}
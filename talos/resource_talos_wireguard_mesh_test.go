@@ -0,0 +1,79 @@
+package talos
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+
+	"terraform-provider-talos/talos/datatypes"
+)
+
+func TestWireguardMeshGenerate(t *testing.T) {
+	mesh := func() *talosWireguardMeshResourceData {
+		return &talosWireguardMeshResourceData{
+			Members: []wireguardMeshMember{
+				{Name: datatypes.Wraps("cp1"), Endpoint: datatypes.Wraps("192.168.1.10:51820"), Address: datatypes.Wraps("10.10.0.1/24"), PrivateKey: types.String{Null: true}},
+				{Name: datatypes.Wraps("cp2"), Endpoint: datatypes.Wraps("192.168.1.11:51820"), Address: datatypes.Wraps("10.10.0.2/24"), PrivateKey: types.String{Null: true}},
+				{Name: datatypes.Wraps("worker1"), Endpoint: types.String{Null: true}, Address: datatypes.Wraps("10.10.0.3/24"), AllowedIPs: datatypes.Wrapsl("10.20.0.0/16"), PrivateKey: types.String{Null: true}},
+			},
+			ListenPort:                  types.Int64{Null: true},
+			PersistentKeepaliveInterval: datatypes.Wrapi(25),
+		}
+	}
+
+	prior := mesh()
+	if err := prior.generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if prior.ID.Value != "cp1,cp2,worker1" {
+		t.Errorf("unexpected id %q", prior.ID.Value)
+	}
+
+	cp1 := prior.Wireguard["cp1"]
+	if len(cp1.Peers) != 2 || cp1.ListenPort.Value != defaultWireguardPort {
+		t.Fatalf("expected two peers listening on the default port, got %+v", cp1)
+	}
+	if cp1.Peers[0].PublicKey != prior.Members[1].PublicKey || cp1.Peers[0].AllowedIPs[0].Value != "10.10.0.2/32" {
+		t.Errorf("unexpected peer %+v for cp2", cp1.Peers[0])
+	}
+	if worker := cp1.Peers[1]; !worker.Endpoint.Null || len(worker.AllowedIPs) != 2 || worker.AllowedIPs[1].Value != "10.20.0.0/16" {
+		t.Errorf("unexpected peer %+v for worker1", worker)
+	}
+
+	wireguard, err := cp1.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if peers := wireguard.(*v1alpha1.DeviceWireguardConfig).WireguardPeers; len(peers) != 2 || peers[0].WireguardEndpoint != "192.168.1.11:51820" {
+		t.Errorf("unexpected rendered peers %+v", peers)
+	}
+
+	// Keys are kept for members of the previous mesh and generated for new ones.
+	plan := mesh()
+	plan.Members[1].Name = datatypes.Wraps("cp3")
+	if err := plan.generate(prior.Members); err != nil {
+		t.Fatal(err)
+	}
+
+	if plan.Members[0].PrivateKey != prior.Members[0].PrivateKey || plan.Members[2].PublicKey != prior.Members[2].PublicKey {
+		t.Error("keys of existing members not kept")
+	}
+	if plan.Members[1].PrivateKey == prior.Members[1].PrivateKey {
+		t.Error("key of a replaced member kept")
+	}
+}
+
+func TestWireguardDataPeerErrors(t *testing.T) {
+	wireguard := datatypes.Wireguard{
+		PrivateKey: types.String{Null: true},
+		Peers: []datatypes.WireguardPeer{
+			{PublicKey: datatypes.Wraps("not a key"), AllowedIPs: datatypes.Wrapsl("10.10.0.2/32")},
+		},
+	}
+
+	if _, err := wireguard.Data(); err == nil {
+		t.Error("invalid peer accepted")
+	}
+}
@@ -3,16 +3,21 @@ package talos
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net"
+	"sort"
 	"strconv"
 	"terraform-provider-talos/talos/datatypes"
 
 	v1alpha1 "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
 
 	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"google.golang.org/grpc"
 	"gopkg.in/yaml.v2"
 
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+	machinetype "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -23,6 +28,8 @@ import (
 var _ tfsdk.ResourceType = talosWorkerNodeResourceType{}
 var _ tfsdk.Resource = talosWorkerNodeResource{}
 var _ tfsdk.ResourceWithImportState = talosWorkerNodeResource{}
+var _ tfsdk.ResourceWithValidateConfig = talosWorkerNodeResource{}
+var _ tfsdk.ResourceWithModifyPlan = talosWorkerNodeResource{}
 
 type talosWorkerNodeResourceType struct{}
 
@@ -34,20 +41,31 @@ func (t talosWorkerNodeResourceType) GetSchema(_ context.Context) (tfsdk.Schema,
 			"name": {
 				Type:     types.StringType,
 				Required: true,
-				// ValidateFunc: validateDomain,
 				// ForceNew: true,
-				// TODO validate and fix forcenew
+				// TODO fix forcenew
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateDomain(),
+				},
 			},
 			// Install arguments
 			"install_disk": {
-				Type:     types.StringType,
-				Required: true,
+				Type:        types.StringType,
+				Optional:    true,
+				Description: "The disk used for installations. Either install_disk or install_disk_selector must be set.",
+			},
+			"install_disk_selector": {
+				Optional:    true,
+				Description: datatypes.InstallDiskSelectorSchema.Description,
+				Attributes:  tfsdk.SingleNestedAttributes(datatypes.InstallDiskSelectorSchema.Attributes),
 			},
 			"talos_image": {
-				Type:     types.StringType,
-				Required: true,
-				// TODO validate
-				// ValidateFunc: validateImage,
+				Type:        types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Description: "The installer image used to install Talos. Defaults to the image of the base configuration.",
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateImage(),
+				},
 			},
 			"kernel_args": {
 				Type: types.ListType{
@@ -58,15 +76,18 @@ func (t talosWorkerNodeResourceType) GetSchema(_ context.Context) (tfsdk.Schema,
 			"macaddr": {
 				Type:     types.StringType,
 				Required: true,
-				// TODO validate and forcenew
+				// TODO forcenew
 				// ForceNew: true,
-				// ValidateFunc: validateMAC,
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateMAC(),
+				},
 			},
 			"dhcp_network_cidr": {
 				Type:     types.StringType,
 				Required: true,
-				// TODO validate
-				// ValidateFunc: validateCIDR,
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateCIDR(),
+				},
 			},
 			// --- MachineConfig.
 			// See https://www.talos.dev/v1.0/reference/configuration/#machineconfig for full spec.
@@ -116,10 +137,11 @@ func (t talosWorkerNodeResourceType) GetSchema(_ context.Context) (tfsdk.Schema,
 				Type: types.ListType{
 					ElemType: types.StringType,
 				},
-				Optional: true,
-				// TODO validation
-				// validateEndpoint
+				Optional:    true,
 				Description: "Used to statically set the nameservers for the machine.",
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateIP(),
+				},
 			},
 			"extra_host": {
 				Type: types.MapType{
@@ -170,7 +192,11 @@ func (t talosWorkerNodeResourceType) GetSchema(_ context.Context) (tfsdk.Schema,
 			},
 
 			// system_disk_encryption not implemented
-			// features not implemented
+			"features": {
+				Optional:    true,
+				Description: datatypes.FeaturesSchema.Description,
+				Attributes:  tfsdk.SingleNestedAttributes(datatypes.FeaturesSchema.Attributes),
+			},
 			"udev": {
 				Type: types.ListType{
 					ElemType: types.StringType,
@@ -189,22 +215,26 @@ func (t talosWorkerNodeResourceType) GetSchema(_ context.Context) (tfsdk.Schema,
 				Type:      types.StringType,
 				Required:  true,
 				Sensitive: true,
-				/*
-					ValidateFunc: func(value interface{}, key string) (warns []string, errs []error) {
-						v := value.(string)
-						input := generate.Input{}
-						if err := json.Unmarshal([]byte(v), &input); err != nil {
-							errs = append(errs, fmt.Errorf("Failed to  base_config. Do not set this value to anything other than the base_config value of a talos_cluster_config resource"))
-						}
-						return
-					},
-				*/
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateBaseConfig(),
+				},
 			},
 			"config_ip": {
 				Type:     types.StringType,
 				Required: true,
-				// ValidateFunc: validateIP,
+				Validators: []tfsdk.AttributeValidator{
+					datatypes.ValidateIP(),
+				},
 			},
+			"config_patches": configPatchesAttribute,
+			"reset":          resetAttribute,
+			"on_destroy":     onDestroyAttribute,
+			"on_unreachable": onUnreachableAttribute,
+			"runtime_mode":   runtimeModeAttribute,
+			"apply_mode":     applyModeAttributes["apply_mode"],
+			"try_timeout":    applyModeAttributes["try_timeout"],
+			"applied_mode":   applyModeAttributes["applied_mode"],
+			"rebooted":       applyModeAttributes["rebooted"],
 			// Generated
 			"id": {
 				Computed:            true,
@@ -221,6 +251,7 @@ func (t talosWorkerNodeResourceType) GetSchema(_ context.Context) (tfsdk.Schema,
 type talosWorkerNodeResourceData struct {
 	Name            types.String                       `tfsdk:"name"`
 	InstallDisk     types.String                       `tfsdk:"install_disk"`
+	InstallSelector *datatypes.InstallDiskSelector     `tfsdk:"install_disk_selector"`
 	TalosImage      types.String                       `tfsdk:"talos_image"`
 	KernelArgs      []types.String                     `tfsdk:"kernel_args"`
 	Macaddr         types.String                       `tfsdk:"macaddr"`
@@ -238,18 +269,175 @@ type talosWorkerNodeResourceData struct {
 	Sysctls         map[string]types.String            `tfsdk:"sysctls"`
 	Sysfs           map[string]types.String            `tfsdk:"sysfs"`
 	Registry        *datatypes.Registry                `tfsdk:"registry"`
+	Features        *datatypes.FeaturesConfig          `tfsdk:"features"`
 	Udev            []types.String                     `tfsdk:"udev"`
 	ConfigIP        types.String                       `tfsdk:"config_ip"`
+	Patches         []types.String                     `tfsdk:"config_patches"`
+	Reset           *resetOptions                      `tfsdk:"reset"`
+	OnDestroy       types.String                       `tfsdk:"on_destroy"`
+	OnUnreachable   types.String                       `tfsdk:"on_unreachable"`
+	RuntimeMode     types.String                       `tfsdk:"runtime_mode"`
+	ApplyMode       types.String                       `tfsdk:"apply_mode"`
+	TryTimeout      types.String                       `tfsdk:"try_timeout"`
+	AppliedMode     types.String                       `tfsdk:"applied_mode"`
+	Rebooted        types.Bool                         `tfsdk:"rebooted"`
 	BaseConfig      types.String                       `tfsdk:"base_config"`
 	ID              types.String                       `tfsdk:"id"`
 }
 
+// Generate fills in the values derived from the node's base_config and the Talos defaults which aren't configured.
 func (plan *talosWorkerNodeResourceData) Generate() (err error) {
+	return plan.generate(nil)
+}
+
+// generate fills in the node's derived values which aren't configured. Wireguard private keys which aren't
+// configured are taken from prior, the node's previous state, if there is one.
+func (plan *talosWorkerNodeResourceData) generate(prior *talosWorkerNodeResourceData) (err error) {
+	input := generate.Input{}
+	if err := json.Unmarshal([]byte(plan.BaseConfig.Value), &input); err != nil {
+		return fmt.Errorf("unable to marshal node's base_config data into it's generate.Input struct: %w", err)
+	}
+
+	for name, device := range plan.NetworkDevices {
+		if device.Wireguard == nil {
+			continue
+		}
+
+		var previous *datatypes.Wireguard
+		if prior != nil {
+			previous = prior.NetworkDevices[name].Wireguard
+		}
+
+		if err := device.Wireguard.GenerateKeys(previous); err != nil {
+			return fmt.Errorf("unable to generate the Wireguard keys of device %s: %w", name, err)
+		}
+	}
+
+	fillString(&plan.TalosImage, defaultInstallImage(input))
+
+	if plan.Kubelet != nil {
+		fillString(&plan.Kubelet.Image, kubernetesImage(input, constants.KubeletImage, (&v1alpha1.KubeletConfig{}).Image()))
+	}
+
+	if plan.Proxy != nil {
+		fillString(&plan.Proxy.Image, kubernetesImage(input, constants.KubeProxyImage, (&v1alpha1.ProxyConfig{}).Image()))
+	}
+
+	if plan.ControlPlane != nil {
+		fillString(&plan.ControlPlane.Endpoint, input.GetControlPlaneEndpoint())
+	}
+
 	return
 }
 
+func (plan *talosWorkerNodeResourceData) ConfigPatches() []types.String {
+	return plan.Patches
+}
+
+func (plan *talosWorkerNodeResourceData) Runtime() (runtimeMode, error) {
+	return parseRuntimeMode(plan.RuntimeMode)
+}
+
 func (plan *talosWorkerNodeResourceData) ReadInto(in *v1alpha1.Config) (err error) {
-	return
+	if in == nil {
+		return
+	}
+
+	// A worker's attributes are a subset of a control node's, so the same readers are run against a TalosConfig
+	// holding the worker's current values.
+	cfg := datatypes.TalosConfig{
+		Kubelet:  plan.Kubelet,
+		Proxy:    plan.Proxy,
+		Registry: plan.Registry,
+		Features: plan.Features,
+		Files:    plan.Files,
+		Network:  &datatypes.NetworkConfig{},
+	}
+	for name, device := range plan.NetworkDevices {
+		if device.Selector == nil {
+			device.Name = types.String{Value: name}
+		}
+		cfg.Network.Devices = append(cfg.Network.Devices, device)
+	}
+
+	funcs := []datatypes.ConfigToPlanFunc{
+		datatypes.TalosKubelet{KubeletConfig: in.MachineConfig.MachineKubelet},
+		datatypes.TalosProxyConfig{ProxyConfig: in.ClusterConfig.ProxyConfig},
+		datatypes.TalosRegistriesConfig{RegistriesConfig: &in.MachineConfig.MachineRegistries},
+		datatypes.TalosFeaturesConfig{FeaturesConfig: in.MachineConfig.MachineFeatures},
+		datatypes.TalosInstallConfig{InstallConfig: in.MachineConfig.MachineInstall},
+		datatypes.TalosNetworkConfig{NetworkConfig: in.MachineConfig.MachineNetwork},
+		datatypes.TalosMachineSysfs(in.MachineConfig.MachineSysfs),
+		datatypes.TalosMachineSysctls(in.MachineConfig.MachineSysctls),
+		datatypes.TalosFiles{Files: in.MachineConfig.MachineFiles},
+		datatypes.TalosMachineEnv(in.MachineConfig.MachineEnv),
+		datatypes.TalosMachineUdev{UdevConfig: in.MachineConfig.MachineUdev},
+		datatypes.TalosMachineCertSANs(in.MachineConfig.MachineCertSANs),
+		datatypes.TalosMachinePods(in.MachineConfig.MachinePods),
+	}
+
+	readFuncs := []datatypes.ConfigReadFunc{}
+	readFuncs = datatypes.AppendReadFunc(readFuncs, funcs...)
+	if cfg, err = datatypes.ApplyReadFunc(&cfg, readFuncs); err != nil {
+		return fmt.Errorf("error applying read functions: %w", err)
+	}
+
+	plan.Kubelet = cfg.Kubelet
+	plan.Proxy = cfg.Proxy
+	plan.Registry = cfg.Registry
+	plan.Features = cfg.Features
+	plan.Files = cfg.Files
+	plan.CertSANS = cfg.CertSANS
+	plan.Pod = cfg.Pod
+	plan.Env = cfg.Env
+	plan.Sysctls = cfg.Sysctls
+	plan.Sysfs = cfg.Sysfs
+	plan.Udev = cfg.Udev
+
+	if cfg.Install != nil {
+		plan.InstallDisk = cfg.Install.Disk
+		plan.InstallSelector = cfg.Install.DiskSelector
+		plan.TalosImage = cfg.Install.Image
+		plan.KernelArgs = cfg.Install.KernelArgs
+	}
+
+	plan.Nameservers = cfg.Network.Nameservers
+	plan.ExtraHost = cfg.Network.ExtraHosts
+
+	// Devices are keyed by their interface, their name is only kept if it was set.
+	devices := map[string]datatypes.NetworkDevice{}
+	for _, device := range cfg.Network.Devices {
+		name := device.Name.Value
+		if device.Selector != nil {
+			name = selectorKey(plan.NetworkDevices, *device.Selector)
+		}
+		device.Name = types.String{Null: true}
+		if prior, ok := plan.NetworkDevices[name]; ok {
+			device.Name = prior.Name
+		}
+		devices[name] = device
+	}
+	plan.NetworkDevices = devices
+
+	return nil
+}
+
+// selectorKey returns the key of the device chosen by selector. Devices which aren't planned yet, such as imported
+// ones, are keyed by the first field the selector matches on.
+func selectorKey(devices map[string]datatypes.NetworkDevice, selector datatypes.DeviceSelector) string {
+	for name, device := range devices {
+		if device.Selector != nil && *device.Selector == selector {
+			return name
+		}
+	}
+
+	for _, field := range []types.String{selector.HardwareAddr, selector.BusPath, selector.PCIID, selector.Driver} {
+		if !field.Null && field.Value != "" {
+			return field.Value
+		}
+	}
+
+	return ""
 }
 
 func (plan *talosWorkerNodeResourceData) TalosData(in *v1alpha1.Config) (out *v1alpha1.Config, err error) {
@@ -275,13 +463,15 @@ func (plan *talosWorkerNodeResourceData) TalosData(in *v1alpha1.Config) (out *v1
 	md.MachineNetwork = &v1alpha1.NetworkConfig{}
 	md.MachineNetwork.NetworkHostname = plan.Name.Value
 	md.MachineNetwork.NetworkInterfaces = []*v1alpha1.Device{}
-	// set device interfaces after get as it's the map key
+	// set device interfaces after get as it's the map key, unless the device is chosen by a selector
 	for netInterface, device := range plan.NetworkDevices {
 		dev, err := device.Data()
 		if err != nil {
 			return &v1alpha1.Config{}, err
 		}
-		dev.(*v1alpha1.Device).DeviceInterface = netInterface
+		if device.Selector == nil {
+			dev.(*v1alpha1.Device).DeviceInterface = netInterface
+		}
 		md.MachineNetwork.NetworkInterfaces = append(md.MachineNetwork.NetworkInterfaces, dev.(*v1alpha1.Device))
 	}
 
@@ -301,6 +491,13 @@ func (plan *talosWorkerNodeResourceData) TalosData(in *v1alpha1.Config) (out *v1
 		InstallImage:      plan.TalosImage.Value,
 		InstallBootloader: true,
 	}
+	if plan.InstallSelector != nil {
+		selector, err := plan.InstallSelector.Data()
+		if err != nil {
+			return &v1alpha1.Config{}, err
+		}
+		md.MachineInstall.InstallDiskSelector = selector.(*v1alpha1.InstallDiskSelector)
+	}
 	if plan.KernelArgs != nil {
 		md.MachineInstall.InstallExtraKernelArgs = []string{}
 		for _, arg := range plan.KernelArgs {
@@ -357,6 +554,12 @@ func (plan *talosWorkerNodeResourceData) TalosData(in *v1alpha1.Config) (out *v1
 		md.MachineRegistries = *registries.(*v1alpha1.RegistriesConfig)
 	}
 
+	if plan.Features != nil {
+		if err := datatypes.ApplyDataFunc(out, plan.Features.DataFunc()); err != nil {
+			return &v1alpha1.Config{}, err
+		}
+	}
+
 	md.MachineUdev = &v1alpha1.UdevConfig{}
 	for _, rule := range plan.Udev {
 		md.MachineUdev.UdevRules = append(md.MachineUdev.UdevRules, rule.Value)
@@ -376,6 +579,49 @@ type talosWorkerNodeResource struct {
 	provider provider
 }
 
+// apply renders the node's configuration and applies it through the connection returned by dial, in the node's apply
+// mode or in fallback if none is set. A node in maintenance mode only supports some apply modes, others fall back to
+// rebooting with a warning. The mode Talos applied the configuration in is recorded in applied_mode and rebooted.
+func (plan *talosWorkerNodeResourceData) apply(ctx context.Context, input *generate.Input, dial func() (*grpc.ClientConn, error),
+	fallback machine.ApplyConfigurationRequest_Mode, maintenance bool) (diags diag.Diagnostics) {
+	yaml, err := genConfig(machinetype.TypeWorker, input, plan)
+	if err != nil {
+		diags.Append(configDiagnostics("Unable to generate talos node config.", err)...)
+		return
+	}
+
+	req, err := applyRequest(yaml, plan.ApplyMode, plan.TryTimeout, fallback)
+	if err != nil {
+		diags.AddError("Invalid apply mode.", err.Error())
+		return
+	}
+
+	if maintenance {
+		if warning := maintenanceRequest(req); warning != "" {
+			diags.AddWarning("Apply mode not supported during provisioning.", warning)
+		}
+	}
+
+	conn, err := dial()
+	if err != nil {
+		diags.AddError("Unable to connect to the Talos machine.", err.Error())
+		return
+	}
+
+	applied, err := applyConfigRequest(ctx, conn, req)
+	if err != nil {
+		diags.AddError("Unable to apply node configuration yaml", err.Error())
+		return
+	}
+	plan.AppliedMode, plan.Rebooted = appliedResult(applied)
+
+	for _, warning := range applied.Warnings {
+		diags.AddWarning("Talos configuration warning.", warning)
+	}
+
+	return
+}
+
 func (r talosWorkerNodeResource) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
 	var (
 		plan talosWorkerNodeResourceData
@@ -386,27 +632,36 @@ func (r talosWorkerNodeResource) Create(ctx context.Context, req tfsdk.CreateRes
 		return
 	}
 
-	diags := req.Config.Get(ctx, &plan)
+	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	/*
-		p := &plan
-		config, errDesc, err := applyConfig(ctx, &p, configData{
-			Bootstrap:   false,
-			CreateNode:  true,
-			Mode:        machine.ApplyConfigurationRequest_REBOOT,
-			BaseConfig:  plan.BaseConfig.Value,
-			MachineType: machinetype.TypeWorker,
-		})
+
+	input := generate.Input{}
+	if err := json.Unmarshal([]byte(plan.BaseConfig.Value), &input); err != nil {
+		resp.Diagnostics.AddError("Failed to unmarshal input bundle.", err.Error())
+		return
+	}
+	ctx = maskSecrets(ctx, input, &plan)
+
+	if err := plan.Generate(); err != nil {
+		resp.Diagnostics.AddError("Unable to generate initial plan configuration values.", err.Error())
+		return
+	}
+
+	// Worker nodes are provisioned through the maintenance endpoint at their configuration address.
+	resp.Diagnostics.Append(plan.apply(ctx, &input, func() (*grpc.ClientConn, error) {
+		conn, err := insecureConn(ctx, net.JoinHostPort(plan.ConfigIP.Value, strconv.Itoa(talosPort)))
 		if err != nil {
-			resp.Diagnostics.AddError(errDesc, err.Error())
-			return
+			return nil, fmt.Errorf("unable to make insecure connection to Talos machine: %w", err)
 		}
+		return conn, nil
+	}, machine.ApplyConfigurationRequest_REBOOT, true)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-		plan.Patch = types.String{Value: config}
-	*/
 	plan.ID = types.String{Value: string(plan.Name.Value)}
 	diags = resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -429,17 +684,29 @@ func (r talosWorkerNodeResource) Read(ctx context.Context, req tfsdk.ReadResourc
 		return
 	}
 
-	if !r.provider.skipread {
-		conf, errDesc, err := readConfig(ctx, &state, readData{
-			ConfigIP:   state.ConfigIP.Value,
-			BaseConfig: state.BaseConfig.Value,
-		})
-		if err != nil {
-			resp.Diagnostics.AddError(errDesc, err.Error())
-			return
-		}
+	input := generate.Input{}
+	if err := json.Unmarshal([]byte(state.BaseConfig.Value), &input); err != nil {
+		resp.Diagnostics.AddError("error while unmarshalling Talos node base configuration package", err.Error())
+		return
+	}
+	ctx = maskSecrets(ctx, input, &state)
+
+	if unreachable(ctx, state.OnUnreachable, input, state.ConfigIP.Value, resp) {
+		return
+	}
+
+	conf, errDesc, err := readConfig(ctx, &state, readData{
+		ConfigIP:   state.ConfigIP.Value,
+		BaseConfig: state.BaseConfig.Value,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(errDesc, err.Error())
+		return
+	}
 
-		state.ReadInto(conf)
+	if err = readNormalized(&state, machinetype.TypeWorker, &input, conf); err != nil {
+		resp.Diagnostics.AddError("Error reading talos configuration.", err.Error())
+		return
 	}
 
 	diags = resp.State.Set(ctx, &state)
@@ -461,34 +728,25 @@ func (r talosWorkerNodeResource) Update(ctx context.Context, req tfsdk.UpdateRes
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	/*
-		p := &state
-		config, errDesc, err := applyConfig(ctx, &p, configData{
-			Bootstrap:   false,
-			ConfigIP:    state.ConfigIP.Value,
-			Mode:        machine.ApplyConfigurationRequest_AUTO,
-			BaseConfig:  state.BaseConfig.Value,
-			MachineType: machinetype.TypeWorker,
-		})
-		if err != nil {
-			resp.Diagnostics.AddError(errDesc, err.Error())
-			return
-		}
 
-		state.Patch = types.String{Value: config}
+	input := generate.Input{}
+	if err := json.Unmarshal([]byte(state.BaseConfig.Value), &input); err != nil {
+		resp.Diagnostics.AddError("unmarshal error", "failed to unmarshal input bundle")
+		return
+	}
+	ctx = maskSecrets(ctx, input, &state)
 
-		if !r.provider.skipread {
-			conf, errDesc, err := readConfig(ctx, &state, readData{
-				ConfigIP:   state.ConfigIP.Value,
-				BaseConfig: state.BaseConfig.Value,
-			})
-			if err != nil {
-				resp.Diagnostics.AddError(errDesc, err.Error())
-				return
-			}
-			state.ReadInto(conf)
+	resp.Diagnostics.Append(state.apply(ctx, &input, func() (*grpc.ClientConn, error) {
+		conn, err := secureConn(ctx, input, net.JoinHostPort(state.ConfigIP.Value, strconv.Itoa(talosPort)))
+		if err != nil {
+			return nil, fmt.Errorf("unable to make secure connection to Talos machine: %w", err)
 		}
-	*/
+		return conn, nil
+	}, machine.ApplyConfigurationRequest_AUTO, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	state.ID = types.String{Value: string(state.Name.Value)}
 
 	diags = resp.State.Set(ctx, &state)
@@ -497,47 +755,171 @@ func (r talosWorkerNodeResource) Update(ctx context.Context, req tfsdk.UpdateRes
 
 func (r talosWorkerNodeResource) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
 	var (
-		state talosControlNodeResourceData
+		state talosWorkerNodeResourceData
 	)
 
 	if !r.provider.configured {
 		resp.Diagnostics.AddError("Provider not configured.", "The Talos worker node resource's Read method has been called without the provider being configured. This is a provider bug.")
 	}
 
-	if r.provider.skipdelete {
-		return
-	}
-
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	host := net.JoinHostPort(state.ConfigIP.Value, strconv.Itoa(talosPort))
+	if !resetOnDestroy(state.OnDestroy, &resp.Diagnostics) {
+		return
+	}
 
 	input := generate.Input{}
 	if err := json.Unmarshal([]byte(state.BaseConfig.Value), &input); err != nil {
 		resp.Diagnostics.AddError("error while unmarshalling Talos node bae configuration package", err.Error())
 		return
 	}
+	ctx = maskSecrets(ctx, input, &state)
+
+	// Worker nodes return to maintenance mode at their configuration address.
+	resp.Diagnostics.Append(decommissionNode(ctx, input, state.ConfigIP.Value, state.Name.Value, false, nil, state.Reset, state.ConfigIP.Value)...)
+}
+
+// ModifyPlan computes the node's derived values, such as the installer image and Wireguard keys, so they are shown in
+// the plan. Changes to an existing node are dry run so their effect, such as whether the node will reboot, is shown
+// as warnings in the plan.
+func (r talosWorkerNodeResource) ModifyPlan(ctx context.Context, req tfsdk.ModifyResourcePlanRequest, resp *tfsdk.ModifyResourcePlanResponse) {
+	var (
+		plan  talosWorkerNodeResourceData
+		state talosWorkerNodeResourceData
+		prior *talosWorkerNodeResourceData
+	)
+
+	// Nothing to plan when the node is being destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	decoded, diags := decodeConfig(ctx, req.Config, &plan)
+	resp.Diagnostics.Append(diags...)
+	if !decoded || plan.BaseConfig.Unknown {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		if diags := req.State.Get(ctx, &state); diags.HasError() {
+			return
+		}
+		prior = &state
+	}
+
+	if err := plan.generate(prior); err != nil {
+		resp.Diagnostics.AddError("Unable to generate the node's derived configuration values.", err.Error())
+		return
+	}
+
+	planGenerated(ctx, &plan, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	conn, err := secureConn(ctx, input, host)
+	input := generate.Input{}
+	if err := json.Unmarshal([]byte(plan.BaseConfig.Value), &input); err != nil {
+		return
+	}
+	ctx = maskSecrets(ctx, input, &plan)
+
+	after, err := genConfig(machinetype.TypeWorker, &input, &plan)
 	if err != nil {
-		resp.Diagnostics.AddError("error while attempting to connect to Talos API endpoint", err.Error())
+		resp.Diagnostics.Append(configDiagnostics("Unable to generate talos node config.", err)...)
 		return
 	}
-	defer conn.Close()
 
-	client := machine.NewMachineServiceClient(conn)
-	_, err = client.Reset(ctx, &machine.ResetRequest{
-		Graceful: false,
-		Reboot:   true,
-	})
+	var images []componentImage
+	if plan.Kubelet != nil {
+		images = append(images, componentImage{path.Root("kubelet").AtName("image"), plan.Kubelet.Image})
+	}
+	if plan.Proxy != nil {
+		images = append(images, componentImage{path.Root("proxy").AtName("image"), plan.Proxy.Image})
+	}
+	resp.Diagnostics.Append(warnImageVersions(input, images)...)
+
+	// Configuration holding values which aren't known yet is validated once they are.
+	if req.Config.Raw.IsFullyKnown() {
+		resp.Diagnostics.Append(validateRendered(plan.RuntimeMode, after)...)
+	}
+
+	// Nothing to dry run when the node is being created.
+	if resp.Diagnostics.HasError() || prior == nil {
+		return
+	}
+
+	before, err := genConfig(machinetype.TypeWorker, &input, &state)
+	if err != nil {
+		return
+	}
+
+	if string(before) == string(after) {
+		return
+	}
+
+	applyReq, err := applyRequest(after, plan.ApplyMode, plan.TryTimeout, machine.ApplyConfigurationRequest_AUTO)
 	if err != nil {
-		resp.Diagnostics.AddError("error while attempting to connect to reset maachine", err.Error())
+		resp.Diagnostics.AddAttributeError(path.Root("apply_mode"), "Invalid apply mode.", err.Error())
 		return
 	}
+
+	resp.Diagnostics.Append(dryRun(ctx, input, plan.Name.Value, state.ConfigIP.Value, before, after, applyReq)...)
+}
+
+// ValidateConfig checks the relationships between the node's network devices, kubelet arguments and install disk,
+// which Talos only rejects once the node is configured.
+func (r talosWorkerNodeResource) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var config talosWorkerNodeResourceData
+
+	decoded, diags := decodeConfig(ctx, req.Config, &config)
+	resp.Diagnostics.Append(diags...)
+	if !decoded {
+		return
+	}
+
+	names := make([]string, 0, len(config.NetworkDevices))
+	for name := range config.NetworkDevices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	devices := make([]datatypes.NetworkDevice, 0, len(names))
+	paths := make([]path.Path, 0, len(names))
+	for _, name := range names {
+		// The interface name of a device is its key, unless the device is chosen by a selector.
+		device := config.NetworkDevices[name]
+		if device.Name.Null && device.Selector == nil {
+			device.Name = types.String{Value: name}
+		}
+		devices = append(devices, device)
+		paths = append(paths, path.Root("devices").AtMapKey(name))
+	}
+
+	resp.Diagnostics.Append(datatypes.ValidateDevices(devices, paths)...)
+	resp.Diagnostics.Append(validateApplyMode(config.ApplyMode, config.TryTimeout, path.Empty())...)
+	resp.Diagnostics.Append(config.Reset.validate(path.Root("reset"))...)
+
+	resp.Diagnostics.Append(datatypes.ValidateKubelet(config.Kubelet, path.Root("kubelet"))...)
+	resp.Diagnostics.Append(datatypes.ValidateInstallDisk(config.InstallDisk, config.InstallSelector, path.Root("install_disk_selector"))...)
+	if config.InstallDisk.Null && config.InstallSelector == nil {
+		resp.Diagnostics.AddAttributeError(path.Root("install_disk"), "Missing install disk.", "Either install_disk or install_disk_selector must be set.")
+	}
+
+	for i, device := range devices {
+		if device.VIP != nil {
+			resp.Diagnostics.AddAttributeError(paths[i].AtName("vip"), "VIP on a worker node.", "Virtual shared IPs are only supported on control plane nodes.")
+		}
+		for j, vlan := range device.VLANs {
+			if vlan.VIP != nil {
+				resp.Diagnostics.AddAttributeError(paths[i].AtName("vlans").AtListIndex(j).AtName("vip"), "VIP on a worker node.",
+					"Virtual shared IPs are only supported on control plane nodes.")
+			}
+		}
+	}
 }
 
 func (r talosWorkerNodeResource) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
@@ -1 +1,123 @@
 package talos
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"terraform-provider-talos/talos/datatypes"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/config/configloader"
+	machinetype "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeMachineService records the configuration applied to it and applies it in the requested mode, or in
+// appliedMode if it is set, as Talos does when it resolves the auto mode.
+type fakeMachineService struct {
+	machine.UnimplementedMachineServiceServer
+
+	appliedMode *machine.ApplyConfigurationRequest_Mode
+	request     *machine.ApplyConfigurationRequest
+}
+
+func (s *fakeMachineService) ApplyConfiguration(ctx context.Context, req *machine.ApplyConfigurationRequest) (*machine.ApplyConfigurationResponse, error) {
+	s.request = req
+
+	mode := req.Mode
+	if s.appliedMode != nil {
+		mode = *s.appliedMode
+	}
+
+	return &machine.ApplyConfigurationResponse{
+		Messages: []*machine.ApplyConfiguration{{Mode: mode, Warnings: []string{"fake warning"}}},
+	}, nil
+}
+
+// dial starts the service and returns a function connecting to it.
+func (s *fakeMachineService) dial(t *testing.T) func() (*grpc.ClientConn, error) {
+	listener := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	machine.RegisterMachineServiceServer(srv, s)
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	return func() (*grpc.ClientConn, error) {
+		return grpc.Dial("bufnet", grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}))
+	}
+}
+
+// TestWorkerApply checks that a worker's configuration is applied in its apply mode, that provisioning falls back
+// to the modes supported in maintenance mode and that the mode Talos applied the configuration in is recorded.
+func TestWorkerApply(t *testing.T) {
+	base, err := json.Marshal(datatypes.InputBundleExample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auto := machine.ApplyConfigurationRequest_AUTO
+	noReboot := machine.ApplyConfigurationRequest_NO_REBOOT
+	tests := []struct {
+		name        string
+		mode        types.String
+		fallback    machine.ApplyConfigurationRequest_Mode
+		maintenance bool
+		appliedMode *machine.ApplyConfigurationRequest_Mode
+		requested   machine.ApplyConfigurationRequest_Mode
+		applied     string
+		rebooted    bool
+		warnings    int
+	}{
+		{"provision", types.String{Null: true}, machine.ApplyConfigurationRequest_REBOOT, true, nil, machine.ApplyConfigurationRequest_REBOOT, "reboot", true, 1},
+		{"provision in try mode", types.String{Value: "try"}, machine.ApplyConfigurationRequest_REBOOT, true, nil, machine.ApplyConfigurationRequest_REBOOT, "reboot", true, 2},
+		{"update", types.String{Null: true}, machine.ApplyConfigurationRequest_AUTO, false, &noReboot, auto, "no_reboot", false, 1},
+		{"update in staged mode", types.String{Value: "staged"}, machine.ApplyConfigurationRequest_AUTO, false, nil, machine.ApplyConfigurationRequest_STAGED, "staged", false, 1},
+	}
+
+	for _, tt := range tests {
+		plan := &talosWorkerNodeResourceData{
+			Name:        datatypes.Wraps("worker-1"),
+			InstallDisk: datatypes.Wraps("/dev/sda"),
+			ApplyMode:   tt.mode,
+			TryTimeout:  types.String{Null: true},
+			BaseConfig:  types.String{Value: string(base)},
+		}
+		if err := plan.Generate(); err != nil {
+			t.Fatal(err)
+		}
+
+		service := &fakeMachineService{appliedMode: tt.appliedMode}
+		diags := plan.apply(context.Background(), &datatypes.InputBundleExample, service.dial(t), tt.fallback, tt.maintenance)
+		if diags.HasError() {
+			t.Errorf("%s: %v", tt.name, diags)
+			continue
+		}
+
+		if service.request.Mode != tt.requested {
+			t.Errorf("%s: expected the configuration to be applied in %s mode, got %s", tt.name, tt.requested, service.request.Mode)
+		}
+
+		cfg, err := configloader.NewFromBytes(service.request.Data)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if cfg.Machine().Type() != machinetype.TypeWorker {
+			t.Errorf("%s: expected a worker configuration, got %s", tt.name, cfg.Machine().Type())
+		}
+
+		if plan.AppliedMode.Value != tt.applied || plan.Rebooted.Value != tt.rebooted {
+			t.Errorf("%s: expected applied mode %s and rebooted %t, got %s and %t", tt.name, tt.applied, tt.rebooted, plan.AppliedMode.Value, plan.Rebooted.Value)
+		}
+
+		if len(diags.Warnings()) != tt.warnings {
+			t.Errorf("%s: expected %d warnings, got %v", tt.name, tt.warnings, diags)
+		}
+	}
+}
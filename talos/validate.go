@@ -1,215 +1,76 @@
 package talos
 
-// Schema validation helpers
+import (
+	"fmt"
 
-/*
-	ValidateFunc: func(value interface{}, key string) (warns []string, errs []error) {
-		v := value.(string)
-		config := kubeval.NewDefaultConfig()
-		schemaCache := kubeval.NewSchemaCache()
-		_, err := kubeval.ValidateWithCache([]byte(v), schemaCache, config)
-		if err != nil {
-			errs = append(errs, fmt.Errorf("Invalid kubernetes manifest provided"))
-			errs = append(errs, err)
-		}
-		return
-	},
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/config/configloader"
+)
 
-// validateDomain checks whether the provided schema value is a valid domain name.
-func validateDomain(value interface{}, key string) (warns []string, errs []error) {
-	v := value.(string)
-	pattern := `[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*`
-	if !regexp.MustCompile(pattern).Match([]byte(v)) {
-		errs = append(errs, fmt.Errorf("%s: Node name must be a lowercase RFC 1123 subdomain, got \"%s\"", key, v))
-	}
-	return
-}
+// runtimeMode is the environment Talos runs in, a rendered configuration is validated for it. It mirrors the modes of
+// Talos' machined, only metal installs Talos to disk.
+type runtimeMode string
 
-// validateDomain checks whether the provided schema value is a valid MAC address.
-func validateMAC(value interface{}, key string) (warns []string, errs []error) {
-	v := value.(string)
-	if _, err := net.ParseMAC(v); err != nil {
-		errs = append(errs, fmt.Errorf("%s: Must provide a valid MAC address, got \"%s\", error \"%s\"", key, v, err.Error()))
-	}
-	return
-}
+const (
+	runtimeModeMetal     runtimeMode = "metal"
+	runtimeModeCloud     runtimeMode = "cloud"
+	runtimeModeContainer runtimeMode = "container"
+)
 
-// validateDomain checks whether the provided schema value is a valid MAC address.
-func validateCIDR(value interface{}, key string) (warns []string, errs []error) {
-	v := value.(string)
-	if _, _, err := net.ParseCIDR(v); err != nil {
-		errs = append(errs, fmt.Errorf("%s: Must provide a valid CIDR IP address, got \"%s\", error \"%s\"", key, v, err.Error()))
-	}
-	return
-}
+var runtimeModes = []string{string(runtimeModeMetal), string(runtimeModeCloud), string(runtimeModeContainer)}
 
-// validateIP checks whether the provided schema value is a valid IP address.
-func validateIP(value interface{}, key string) (warns []string, errs []error) {
-	v := value.(string)
-	if net.ParseIP(v) == nil {
-		errs = append(errs, fmt.Errorf("%s: Must provide a valid IP address, got \"%s\"", key, v))
-	}
-	return
+// String returns the mode's name.
+func (m runtimeMode) String() string {
+	return string(m)
 }
 
-// validateIP checks whether the provided schema value is a valid hostname.
-func validateHost(value interface{}, key string) (warns []string, errs []error) {
-	v := value.(string)
-	pattern := `[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*:[0-9]{2,}`
-	if !regexp.MustCompile(pattern).Match([]byte(v)) {
-		errs = append(errs, fmt.Errorf("%s: Node name must be a lowercase RFC 1123 subdomain with a port appended, seperated by \":\", got \"%s\"", key, v))
-	}
-	return
+// RequiresInstall reports whether Talos is installed to disk in the mode.
+func (m runtimeMode) RequiresInstall() bool {
+	return m == runtimeModeMetal
 }
 
-// validateIP checks whether the provided schema value is a valid wireguard endpoint.
-func validateEndpoint(value interface{}, key string) (warns []string, errs []error) {
-	v := value.(string)
-	pattern := `[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*(:[0-9]{2,})?`
-	if !regexp.MustCompile(pattern).Match([]byte(v)) {
-		errs = append(errs, fmt.Errorf("%s: Node name must be a lowercase RFC 1123 subdomain with an optional port appended, seperated by \":\", got \"%s\"", key, v))
-	}
-	return
+// runtimeModeAttribute is shared by node resources to choose the runtime mode their configuration is validated for.
+var runtimeModeAttribute = tfsdk.Attribute{
+	Type:     types.StringType,
+	Optional: true,
+	MarkdownDescription: "The environment the node runs Talos in, one of `metal`, `cloud` or `container`. The node's configuration is " +
+		"validated for it during plan. Defaults to `metal`.",
 }
 
-// validateIP checks whether the provided schema value is a valid image registry identifier. Used for specifying images for containers in Static Pods.
-func validateImage(value interface{}, key string) (warns []string, errs []error) {
-	v := value.(string)
-	pattern := `[^/]+\.[^/.]+/([^/.]+/)?[^/.]+(:.+)?`
-	if !regexp.MustCompile(pattern).Match([]byte(v)) {
-		errs = append(errs, fmt.Errorf("%s: Node name must be a valid container image, got \"%s\"", key, v))
-	}
-	return
+// parseRuntimeMode returns the runtime mode in a node's runtime_mode attribute value.
+func parseRuntimeMode(value types.String) (runtimeMode, error) {
+	mode, err := policy(value, string(runtimeModeMetal), runtimeModes)
+	return runtimeMode(mode), err
 }
 
-// validateIP checks whether the provided schema value is a valid wireguard public or private key.
-func validateKey(value interface{}, key string) (warns []string, errs []error) {
-	v := value.(string)
-	if _, err := wgtypes.ParseKey(v); err != nil {
-		errs = append(errs, err)
+// validateRendered validates a node's rendered configuration with Talos' own validator for the runtime mode in value.
+// Errors and warnings are returned as diagnostics, so an invalid configuration is rejected before the node is touched.
+func validateRendered(value types.String, rendered []byte) (diags diag.Diagnostics) {
+	mode, err := parseRuntimeMode(value)
+	if err != nil {
+		diags.AddAttributeError(path.Root("runtime_mode"), "Invalid runtime mode.", err.Error())
+		return
 	}
-	return
-}
 
-func StringListSchema(desc string) schema.Schema {
-	return schema.Schema{
-		Type:        schema.TypeList,
-		Required:    true,
-		MinItems:    1,
-		Description: desc,
-		Default:     []string{},
-		Elem: &schema.Schema{
-			Type: schema.TypeString,
-		},
+	cfg, err := configloader.NewFromBytes(rendered)
+	if err != nil {
+		diags.AddError("Unable to load the rendered Talos configuration.", err.Error())
+		return
 	}
-}
 
-func StringListSchemaValidate(desc string, validate schema.SchemaValidateFunc) *schema.Schema {
-	return &schema.Schema{
-		Type:        schema.TypeList,
-		Required:    true,
-		MinItems:    1,
-		Description: desc,
-		Elem: &schema.Schema{
-			Type:         schema.TypeString,
-			ValidateFunc: validate,
-		},
+	warnings, err := cfg.Validate(mode, config.WithLocal())
+	for _, warning := range warnings {
+		diags.AddWarning("Talos configuration warning.", warning)
 	}
-}
 
-func listSuppressor(key string, old string, new string, d *schema.ResourceData) bool {
-
-	return true
-}
-
-// Suppresses output according to the provided suppressListFunc
-func Suppress(suppress schema.SchemaDiffSuppressFunc, in *schema.Schema) (out *schema.Schema) {
-	out = in
-	out.DiffSuppressFunc = suppress
-	return
-}
-
-// Validate is a helper for building terraform resource schemas that denotes the passed schema is Required.
-func Required(in *schema.Schema) (out *schema.Schema) {
-	out = in
-	out.Required = true
-	return
-}
-
-// Validate is a helper for building terraform resource schemas that denotes the passed schema is Optional.
-func Optional(in *schema.Schema) (out *schema.Schema) {
-	out = in
-	out.Optional = true
-	return
-}
-
-// Validate is a helper for building terraform resource schemas that adds validation to the passed schema.
-func Validate(in *schema.Schema, validate schema.SchemaValidateFunc) (out *schema.Schema) {
-	out = in
-	out.ValidateFunc = validate
-	return
-}
-
-// StringMap is a helper for building terraform resource schemas that adds validation to the elements to the passed schema.
-func ValidateInner(in *schema.Schema, validate schema.SchemaValidateFunc) (out *schema.Schema) {
-	out = in
-	out.Elem.(*schema.Schema).ValidateFunc = validate
-	return
-}
-
-func IsValidPath(value interface{}) (ok bool, err error) {
-	v := value.(string)
-	// A valid filesystem path must not contain spaces
-	if ok = !regexp.MustCompile(`\s`).Match([]byte(v)); !ok {
-		err = fmt.Errorf("%s: Invalid mount path, must not contain spaces.", v)
+	if err != nil {
+		diags.AddError("Invalid Talos configuration.", fmt.Sprintf("The node's configuration is rejected by Talos in %s mode: %s", mode, err))
 	}
-	return
-}
-
-type talosAttributeValidator struct {
-	description         string
-	markdownDescription string
-	validate            func(context.Context, tfsdk.ValidateAttributeRequest, *tfsdk.ValidateAttributeResponse)
-}
-
-type TalosAttributeValidator interface {
-	tfsdk.AttributeValidator
-}
-
-func (v *talosAttributeValidator) Description(context.Context) string {
-	return v.description
-}
 
-func (v *talosAttributeValidator) MarkdownDescription(context.Context) string {
-	return v.markdownDescription
-}
-
-func (v *talosAttributeValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
-	v.validate(ctx, req, resp)
-}
-
-// AllElemsValid takes a function that returns false whenever the field fails validation and returns a function that validates a ListType.
-func AllElemsValid(f func(interface{}) (bool, error)) TalosAttributeValidator {
-	val := new(talosAttributeValidator)
-	val.description = "AllElemsValid takes a function that returns false whenever the field fails validation uses it to check all fields of a ListType."
-	val.markdownDescription = val.description
-	val.validate = func(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
-		l, err := req.AttributeConfig.ToTerraformValue(ctx)
-		if err != nil {
-			resp.Diagnostics.AddError(fmt.Sprintf("Unable to transform given typeList %s to a terraform value.", req.AttributePath.String()), err.Error())
-			return
-		}
-		list := []interface{}{}
-		l.As(list)
-
-		for _, v := range list {
-			if ok, err := f(v); !ok {
-				resp.Diagnostics.AddAttributeError(req.AttributePath, "List object failed validation, error from validator function shown below.", err.Error())
-				return
-			}
-		}
-	}
-	return val
+	return
 }
-*/